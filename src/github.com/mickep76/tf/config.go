@@ -1,218 +1,209 @@
 package main
 
 import (
+	"errors"
 	"fmt"
-)
 
-// CfgDefault contains input configuration defaults.
-type CfgDefault struct {
-	EtcdHost      *string
-	EtcdPort      *int64
-	HTTPHeader    *string
-	HTTPFormat    *string
-	MySQLUser     *string
-	MySQLPassword *string
-	MySQLHost     *string
-	MySQLPort     *int64
-	MySQLDatabase *string
-}
+	"github.com/mickep76/tf/input"
+)
 
 // CfgInput contains input configuration.
 type CfgInput struct {
-	Name          *string
-	Type          *string
-	Path          *string
-	EtcdHost      *string
-	EtcdPort      *int64
-	EtcdDir       *string
-	HTTPUrl       *string
-	HTTPHeader    *string
-	HTTPFormat    *string
-	MySQLUser     *string
-	MySQLPassword *string
-	MySQLHost     *string
-	MySQLPort     *int64
-	MySQLDatabase *string
-	MySQLQuery    *string
+	Name     string
+	Type     string
+	Provider input.Provider
+	Options  input.Options
 }
 
-// GetDefaults gets input defaults from the config file.
-func GetDefaults(defs map[string]interface{}) (CfgDefault, error) {
-	var d CfgDefault
-	for k, v := range defs {
-		switch k {
-		case "etcd_host":
-			s := v.(string)
-			d.EtcdHost = &s
-		case "etcd_port":
-			n := v.(int64)
-			d.EtcdPort = &n
-		case "http_header":
-			s := v.(string)
-			d.HTTPHeader = &s
-		case "http_format":
-			s := v.(string)
-			d.HTTPFormat = &s
-		case "mysql_user":
-			s := v.(string)
-			d.MySQLUser = &s
-		case "mysql_password":
-			s := v.(string)
-			d.MySQLPassword = &s
-		case "mysql_host":
-			s := v.(string)
-			d.MySQLHost = &s
-		case "mysql_port":
-			n := v.(int64)
-			d.MySQLPort = &n
-		case "mysql_database":
-			s := v.(string)
-			d.MySQLDatabase = &s
-		default:
-			return CfgDefault{}, fmt.Errorf("Invalid configuration key \"%v\" in [defaults]", k)
-		}
-	}
-	return d, nil
+// Config contains the configuration file.
+type Config struct {
+	Values   map[string]interface{}
+	Defaults map[string]interface{}
+	Inputs   map[string]map[string]interface{}
+	Merge    map[string]interface{}
 }
 
-// GetInput gets inputs from the configuration file.
-func GetInput(name string, inp map[string]interface{}, d CfgDefault) (CfgInput, error) {
-	var i CfgInput
+// LoadConfig loads a configuration file, YAML maps with interface{} keys are converted to maps with string keys.
+func LoadConfig(fn string, data map[string]interface{}) (Config, error) {
+	v, err := input.LoadFile(fn, data)
+	if err != nil {
+		return Config{}, err
+	}
 
-	if d.EtcdHost != nil {
-		i.EtcdHost = d.EtcdHost
+	cfg, ok := input.StringMap(v)
+	if !ok {
+		return Config{}, errors.New("Incorrect configuration file, it needs to be a map of values")
 	}
-	if d.EtcdPort != nil {
-		i.EtcdPort = d.EtcdPort
-	} else {
-		n := int64(2379)
-		i.EtcdPort = &n
+	c := Config{Values: cfg}
+
+	if cfg["inputs"] == nil {
+		return Config{}, errors.New("No inputs specified in configuration file")
 	}
-	if d.HTTPHeader != nil {
-		i.HTTPHeader = d.HTTPHeader
-	} else {
-		s := "Accept: application/json"
-		i.HTTPHeader = &s
+
+	inps, ok := input.StringMap(cfg["inputs"])
+	if !ok {
+		return Config{}, errors.New("Incorrect definition of inputs, it needs to be a map of values")
 	}
-	if d.HTTPFormat != nil {
-		i.HTTPFormat = d.HTTPFormat
-	} else {
-		s := "JSON"
-		i.HTTPFormat = &s
+
+	c.Inputs = make(map[string]map[string]interface{}, len(inps))
+	for k, v := range inps {
+		inp, ok := input.StringMap(v)
+		if !ok {
+			return Config{}, fmt.Errorf("Incorrect definition of input [inputs.%v], it needs to be a map of values", k)
+		}
+		c.Inputs[k] = inp
 	}
-	if d.MySQLUser != nil {
-		i.MySQLUser = d.MySQLUser
+
+	if cfg["defaults"] != nil {
+		if c.Defaults, ok = input.StringMap(cfg["defaults"]); !ok {
+			return Config{}, errors.New("Incorrect definition of defaults, it needs to be a map of values")
+		}
 	}
-	if d.MySQLPassword != nil {
-		i.MySQLPassword = d.MySQLPassword
+
+	if m, ok := input.StringMap(cfg["merge"]); ok {
+		c.Merge = m
 	}
-	if d.MySQLHost != nil {
-		i.MySQLHost = d.MySQLHost
+
+	return c, nil
+}
+
+// GetDefaults gets input defaults from the config file.
+func GetDefaults(defs map[string]interface{}) (map[string]interface{}, error) {
+	d := make(map[string]interface{})
+	for k, v := range defs {
+		o, ok := input.SharedOption(k)
+		if !ok {
+			return nil, fmt.Errorf("Invalid configuration key \"%v\" in [defaults]", k)
+		}
+
+		c, err := o.Convert(v)
+		if err != nil {
+			return nil, fmt.Errorf("%v in [defaults]", err)
+		}
+		d[k] = c
 	}
-	if d.MySQLPort != nil {
-		i.MySQLPort = d.MySQLPort
+	return d, nil
+}
+
+// GetInput gets inputs from the configuration file.
+func GetInput(name string, inp map[string]interface{}, d map[string]interface{}) (CfgInput, error) {
+	i := CfgInput{Name: name}
+
+	if t, ok := inp["type"].(string); ok {
+		i.Type = t
 	} else {
-		n := int64(3306)
-		i.MySQLPort = &n
+		return CfgInput{}, fmt.Errorf("For input [inputs.%v] you need to specify \"type\"", name)
 	}
-	if d.MySQLDatabase != nil {
-		i.MySQLDatabase = d.MySQLDatabase
+
+	p, ok := input.GetProvider(i.Type)
+	if !ok {
+		return CfgInput{}, fmt.Errorf("Unknown type \"%v\" for input [inputs.%v]", i.Type, name)
+	}
+	i.Provider = p
+
+	i.Options = input.NewOptions(p)
+	for _, o := range input.AllOptions(p) {
+		if v, ok := d[o.Key]; ok && o.Shared {
+			i.Options[o.Key] = v
+		}
 	}
 
-	i.Name = &name
 	for k, v := range inp {
 		switch k {
 		case "name":
-			s := v.(string)
-			i.Name = &s
+			i.Name = v.(string)
 		case "type":
-			s := v.(string)
-			i.Type = &s
-		case "path":
-			s := v.(string)
-			i.Path = &s
-		case "etcd_host":
-			s := v.(string)
-			i.EtcdHost = &s
-		case "etcd_port":
-			n := v.(int64)
-			i.EtcdPort = &n
-		case "etcd_dir":
-			s := v.(string)
-			i.EtcdDir = &s
-		case "http_url":
-			s := v.(string)
-			i.HTTPUrl = &s
-		case "http_header":
-			s := v.(string)
-			i.HTTPHeader = &s
-		case "http_format":
-			s := v.(string)
-			i.HTTPFormat = &s
-		case "mysql_user":
-			s := v.(string)
-			i.MySQLUser = &s
-		case "mysql_password":
-			s := v.(string)
-			i.MySQLPassword = &s
-		case "mysql_host":
-			s := v.(string)
-			i.MySQLHost = &s
-		case "mysql_port":
-			n := v.(int64)
-			i.MySQLPort = &n
-		case "mysql_database":
-			s := v.(string)
-			i.MySQLDatabase = &s
-		case "mysql_query":
-			s := v.(string)
-			i.MySQLQuery = &s
 		default:
-			return CfgInput{}, fmt.Errorf("Invalid configuration key \"%v\" in [inputs.%v]", k, name)
+			o, ok := input.FindOption(p, k)
+			if !ok {
+				return CfgInput{}, fmt.Errorf("Invalid configuration key \"%v\" in [inputs.%v]", k, name)
+			}
+
+			c, err := o.Convert(v)
+			if err != nil {
+				return CfgInput{}, fmt.Errorf("%v in [inputs.%v]", err, name)
+			}
+			i.Options[k] = c
 		}
 	}
 
-	switch *i.Type {
-	case "file":
-		if i.Path == nil {
-			return CfgInput{}, fmt.Errorf("For input [inputs.%v] type \"file\" you need to specify \"path\"", name)
-		}
-	case "etcd":
-		if i.EtcdHost == nil {
-			return CfgInput{}, fmt.Errorf("For input [inputs.%v] type \"etcd\" you need to specify \"etcd_host\"", name)
-		}
-		if i.EtcdPort == nil {
-			return CfgInput{}, fmt.Errorf("For input [inputs.%v] type \"etcd\" you need to specify \"etcd_port\"", name)
-		}
-	case "http":
-		if i.HTTPUrl == nil {
-			return CfgInput{}, fmt.Errorf("For input [inputs.%v] type \"http\" you need to specify \"http_url\"", name)
-		}
-		if i.HTTPHeader == nil {
-			return CfgInput{}, fmt.Errorf("For input [inputs.%v] type \"http\" you need to specify \"http_header\"", name)
-		}
-		if i.HTTPFormat == nil {
-			return CfgInput{}, fmt.Errorf("For input [inputs.%v] type \"http\" you need to specify \"http_format\"", name)
-		}
-	case "mysql":
-		if i.MySQLUser == nil {
-			return CfgInput{}, fmt.Errorf("For input [inputs.%v] type \"mysql\" you need to specify \"mysql_user\"", name)
+	if k := input.Missing(p, i.Options); k != "" {
+		return CfgInput{}, fmt.Errorf("For input [inputs.%v] type \"%v\" you need to specify \"%v\"", name, i.Type, k)
+	}
+
+	return i, nil
+}
+
+// Merge namespaces.
+type Merge struct {
+	Name   string
+	Inputs []string
+}
+
+// GetMerge gets a merge from the configuration file.
+func GetMerge(name string, v interface{}) (Merge, error) {
+	m := Merge{Name: name}
+
+	mrg, ok := input.StringMap(v)
+	if !ok {
+		return Merge{}, fmt.Errorf("Incorrect definition of merge [merge.%v], it needs to be a map of values", name)
+	}
+
+	for k, v := range mrg {
+		switch k {
+		case "name":
+			s, ok := v.(string)
+			if !ok {
+				return Merge{}, fmt.Errorf("Incorrect value for \"name\" in [merge.%v]", name)
+			}
+			m.Name = s
+		case "inputs":
+			l, ok := v.([]interface{})
+			if !ok {
+				return Merge{}, fmt.Errorf("Incorrect value for \"inputs\" in [merge.%v], it needs to be a list of input names", name)
+			}
+			for _, e := range l {
+				s, ok := e.(string)
+				if !ok {
+					return Merge{}, fmt.Errorf("Incorrect value for \"inputs\" in [merge.%v], it needs to be a list of input names", name)
+				}
+				m.Inputs = append(m.Inputs, s)
+			}
+		default:
+			return Merge{}, fmt.Errorf("Invalid key in configuration file merge.%v.%v", name, k)
 		}
-		if i.MySQLPassword == nil {
-			return CfgInput{}, fmt.Errorf("For input [inputs.%v] type \"mysql\" you need to specify \"mysql_password\"", name)
+	}
+
+	return m, nil
+}
+
+// MergeInputs merges the inputs into the merge namespace, only inputs with a map of values can be merged.
+func MergeInputs(m Merge, data map[string]interface{}) error {
+	var dst map[string]interface{}
+	if data[m.Name] == nil {
+		dst = make(map[string]interface{})
+	} else {
+		var ok bool
+		if dst, ok = data[m.Name].(map[string]interface{}); !ok {
+			return fmt.Errorf("Can't merge into \"%v\", it isn't a map of values", m.Name)
 		}
-		if i.MySQLHost == nil {
-			return CfgInput{}, fmt.Errorf("For input [inputs.%v] type \"mysql\" you need to specify \"mysql_host\"", name)
+	}
+
+	for _, n := range m.Inputs {
+		if data[n] == nil {
+			return fmt.Errorf("Can't merge input \"%v\" into \"%v\", input doesn't exist", n, m.Name)
 		}
-		if i.MySQLDatabase == nil {
-			return CfgInput{}, fmt.Errorf("For input [inputs.%v] type \"mysql\" you need to specify \"mysql_database\"", name)
+
+		src, ok := data[n].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("Can't merge input \"%v\" into \"%v\", it's a %T and not a map of values", n, m.Name, data[n])
 		}
-		if i.MySQLQuery == nil {
-			return CfgInput{}, fmt.Errorf("For input [inputs.%v] type \"mysql\" you need to specify \"mysql_query\"", name)
+
+		for k, v := range src {
+			dst[k] = v
 		}
-	default:
-		return CfgInput{}, fmt.Errorf("Unknown type \"%v\" for input [inputs.%v]", *i.Type, *i.Name)
 	}
 
-	return i, nil
+	data[m.Name] = dst
+	return nil
 }
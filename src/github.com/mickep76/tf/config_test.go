@@ -0,0 +1,75 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_LoadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tf")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	fn := filepath.Join(dir, "tf.yaml")
+	c := `defaults:
+  etcd_host: etcd1.example.com
+  etcd_port: 4001
+inputs:
+  hosts:
+    type: etcd
+    etcd_dir: /hosts
+  app:
+    type: file
+    path: app.json
+merge:
+  all:
+    inputs: [hosts, app]
+`
+	if err := ioutil.WriteFile(fn, []byte(c), 0644); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	cfg, err := LoadConfig(fn, map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	defs, err := GetDefaults(cfg.Defaults)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	i, err := GetInput("hosts", cfg.Inputs["hosts"], defs)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if i.Type != "etcd" || i.Options["etcd_host"] != "etcd1.example.com" || i.Options["etcd_port"] != int64(4001) || i.Options["etcd_dir"] != "/hosts" {
+		t.Errorf("LoadConfig didn't return expected input: %v", i.Options)
+	} else {
+		t.Log("LoadConfig test passes")
+	}
+
+	if len(cfg.Inputs) != 2 || cfg.Inputs["app"]["path"] != "app.json" {
+		t.Errorf("LoadConfig didn't return expected inputs: %v", cfg.Inputs)
+	}
+
+	m, err := GetMerge("all", cfg.Merge["all"])
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(m.Inputs) != 2 {
+		t.Errorf("LoadConfig didn't return expected merge: %v", m)
+	}
+
+	if err := ioutil.WriteFile(fn, []byte("defaults: [etcd_host]\ninputs:\n  app:\n    type: file\n"), 0644); err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := LoadConfig(fn, map[string]interface{}{}); err == nil {
+		t.Error("LoadConfig should fail for defaults that aren't a map of values")
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	flags "github.com/jessevdk/go-flags"
+
+	"github.com/mickep76/tf/input"
+)
+
+// InputFlags contains the command line flags for the input providers.
+type InputFlags struct {
+	// Remote contains the remote options that apply to all remote inputs.
+	Remote    *flags.Group
+	Providers []ProviderFlags
+}
+
+// ProviderFlags contains the command line flags for an input provider.
+type ProviderFlags struct {
+	Provider input.Provider
+	Group    *flags.Group
+}
+
+// flagName returns the command line flag for an option key.
+func flagName(key string) string {
+	return strings.Replace(key, "_", "-", -1)
+}
+
+// addFlagGroup adds a group of command line flags for options.
+func addFlagGroup(parser *flags.Parser, name string, opts []input.Option) (*flags.Group, error) {
+	// Build a struct with go-flags tags from the options.
+	var fields []reflect.StructField
+	for i, o := range opts {
+		tag := fmt.Sprintf("long:%q description:%q", flagName(o.Key), o.Description)
+		// Defaults are applied by the provider options, so only show them in the help.
+		if l, ok := o.Default.([]string); ok {
+			tag += fmt.Sprintf(" default-mask:%q", strings.Join(l, ","))
+		} else if o.Default != nil {
+			tag += fmt.Sprintf(" default-mask:%q", fmt.Sprintf("%v", o.Default))
+		}
+
+		ft := reflect.TypeOf("")
+		switch o.Type {
+		case input.OptBool:
+			ft = reflect.TypeOf(false)
+		case input.OptList, input.OptArgs:
+			ft = reflect.TypeOf([]string{})
+		case input.OptMap:
+			ft = reflect.TypeOf(map[string]string{})
+		}
+
+		fields = append(fields, reflect.StructField{
+			Name: fmt.Sprintf("Opt%d", i),
+			Type: ft,
+			Tag:  reflect.StructTag(tag),
+		})
+	}
+
+	return parser.AddGroup(name, "", reflect.New(reflect.StructOf(fields)).Interface())
+}
+
+// setFlags sets the options for flags set on the command line, returns false if none were set.
+func setFlags(g *flags.Group, opts []input.Option, vals input.Options) (bool, error) {
+	set := false
+	for _, fo := range g.Options() {
+		if !fo.IsSet() {
+			continue
+		}
+
+		for _, o := range opts {
+			if flagName(o.Key) != fo.LongName {
+				continue
+			}
+
+			v, err := o.Convert(fo.Value())
+			if err != nil {
+				return false, err
+			}
+			vals[o.Key] = v
+			set = true
+		}
+	}
+	return set, nil
+}
+
+// AddInputFlags adds a group of command line flags for each provider with a namespace.
+func AddInputFlags(parser *flags.Parser) (InputFlags, error) {
+	var inpFlags InputFlags
+
+	var err error
+	if inpFlags.Remote, err = addFlagGroup(parser, "Remote Input Options", input.RemoteOptions); err != nil {
+		return InputFlags{}, err
+	}
+
+	for _, t := range input.Types() {
+		p, _ := input.GetProvider(t)
+		if p.Namespace() == "" {
+			continue
+		}
+
+		g, err := addFlagGroup(parser, fmt.Sprintf("%s Input Options", p.Namespace()), p.Options())
+		if err != nil {
+			return InputFlags{}, err
+		}
+		inpFlags.Providers = append(inpFlags.Providers, ProviderFlags{Provider: p, Group: g})
+	}
+	return inpFlags, nil
+}
+
+// Options returns the provider options set on the command line, returns false if none were set.
+func (f InputFlags) Options(pf ProviderFlags) (input.Options, bool, error) {
+	opts := input.NewOptions(pf.Provider)
+	set, err := setFlags(pf.Group, pf.Provider.Options(), opts)
+	if err != nil || !set {
+		return nil, false, err
+	}
+
+	// Remote options only apply to providers that support them.
+	if input.IsRemote(pf.Provider) {
+		if _, err := setFlags(f.Remote, input.RemoteOptions, opts); err != nil {
+			return nil, false, err
+		}
+	}
+	return opts, true, nil
+}
+
+// RemoteDefaults returns the remote options set on the command line, these override [defaults] in the config file.
+func (f InputFlags) RemoteDefaults() (map[string]interface{}, error) {
+	d := make(input.Options)
+	if _, err := setFlags(f.Remote, input.RemoteOptions, d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
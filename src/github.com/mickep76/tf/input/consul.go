@@ -0,0 +1,108 @@
+package input
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+type consulProvider struct{}
+
+func init() {
+	Register("consul", consulProvider{})
+}
+
+func (consulProvider) Namespace() string {
+	return "Consul"
+}
+
+func (consulProvider) Remote() bool {
+	return true
+}
+
+func (consulProvider) Options() []Option {
+	return []Option{
+		{Key: "consul_address", Description: "Consul address", Default: "http://127.0.0.1:8500", Shared: true},
+		{Key: "consul_token", Description: "Consul ACL token", Shared: true},
+		{Key: "consul_datacenter", Description: "Consul datacenter", Shared: true},
+		{Key: "consul_prefix", Description: "Consul KV prefix", Required: true},
+	}
+}
+
+func (consulProvider) Get(opts Options, data map[string]interface{}) (interface{}, error) {
+	return GetConsul(opts.String("consul_address"), opts.String("consul_token"), opts.String("consul_datacenter"),
+		opts.String("consul_prefix"), opts.Duration("timeout"))
+}
+
+// GetConsul gets a KV prefix recursively from Consul.
+func GetConsul(addr string, token string, dc string, prefix string, timeout time.Duration) (map[string]interface{}, error) {
+	q := url.Values{"recurse": []string{"true"}}
+	if dc != "" {
+		q.Set("dc", dc)
+	}
+	u := fmt.Sprintf("%s/v1/kv/%s?%s", strings.TrimRight(addr, "/"), strings.TrimLeft(prefix, "/"), q.Encode())
+
+	log.Infof("Get Consul KV: %s", u)
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("X-Consul-Token", token)
+	}
+
+	client := &http.Client{Timeout: timeout}
+	r, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	// Consul returns 404 if there are no keys below the prefix.
+	if r.StatusCode == http.StatusNotFound {
+		return make(map[string]interface{}), nil
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	if r.StatusCode != http.StatusOK {
+		return nil, &statusError{code: r.StatusCode, msg: fmt.Sprintf("Consul request failed: %s: %s", r.Status, strings.TrimSpace(string(body)))}
+	}
+
+	var pairs []struct {
+		Key   string
+		Value *string
+	}
+	if err := json.Unmarshal(body, &pairs); err != nil {
+		return nil, err
+	}
+
+	kvs := make(map[string]interface{})
+	for _, p := range pairs {
+		if strings.HasSuffix(p.Key, "/") {
+			kvs[p.Key] = nil
+			continue
+		}
+
+		v := ""
+		if p.Value != nil {
+			b, err := base64.StdEncoding.DecodeString(*p.Value)
+			if err != nil {
+				return nil, err
+			}
+			v = string(b)
+		}
+		kvs[p.Key] = v
+	}
+
+	return KVMap(prefix, kvs), nil
+}
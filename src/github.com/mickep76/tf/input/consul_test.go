@@ -0,0 +1,37 @@
+package input
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_GetConsul(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/kv/hosts" || r.URL.Query().Get("recurse") != "true" || r.Header.Get("X-Consul-Token") != "secret" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`[
+			{"Key": "hosts/", "Value": null},
+			{"Key": "hosts/host1.example.com/serialno", "Value": "YWJjMTIz"},
+			{"Key": "hosts/host2.example.com/serialno", "Value": "ZGVmNDU2"},
+			{"Key": "hosts/host3.example.com/", "Value": null}
+		]`))
+	}))
+	defer ts.Close()
+
+	v, err := GetConsul(ts.URL, "secret", "", "hosts", time.Second)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	h1, _ := v["host1.example.com"].(map[string]interface{})
+	h3, _ := v["host3.example.com"].(map[string]interface{})
+	if len(v) != 3 || h1["serialno"] != "abc123" || h3 == nil {
+		t.Errorf("GetConsul didn't return expected result: %v", v)
+	} else {
+		t.Log("GetConsul test passes")
+	}
+}
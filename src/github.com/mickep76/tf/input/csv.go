@@ -0,0 +1,112 @@
+package input
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// DefaultDelimiter returns the default delimiter for a data format, "\t" for TSV otherwise ",".
+func DefaultDelimiter(f DataFmt) rune {
+	if f == TSV {
+		return '\t'
+	}
+	return ','
+}
+
+// ParseDelimiter parses a CSV delimiter, a single character or "\t" and "tab" for tab.
+func ParseDelimiter(s string) (rune, error) {
+	switch s {
+	case `\t`, "tab":
+		return '\t', nil
+	}
+
+	r, n := utf8.DecodeRuneInString(s)
+	if n == 0 || n != len(s) || r == '"' || r == '\r' || r == '\n' || r == utf8.RuneError {
+		return 0, fmt.Errorf("Incorrect CSV delimiter: %q, needs to be a single character", s)
+	}
+	return r, nil
+}
+
+// UnmarshalCSV unmarshal CSV data with a header row, rows are returned as a list of maps keyed by the header.
+func UnmarshalCSV(cont []byte, delim rune) ([]interface{}, error) {
+	r := csv.NewReader(bytes.NewReader(cont))
+	r.Comma = delim
+	if delim == '\t' {
+		r.LazyQuotes = true
+	}
+
+	header, err := r.Read()
+	if err == io.EOF {
+		return []interface{}{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	l := []interface{}{}
+	for {
+		rec, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		m := make(map[string]interface{}, len(header))
+		for i, h := range header {
+			m[h] = rec[i]
+		}
+		l = append(l, m)
+	}
+	return l, nil
+}
+
+// UnmarshalDataDelim unmarshal serialized data like UnmarshalData, but uses a custom delimiter for CSV and TSV.
+// A delimiter of 0 uses the default for the data format.
+func UnmarshalDataDelim(cont []byte, f DataFmt, delim rune) (interface{}, error) {
+	if delim == 0 || (f != CSV && f != TSV) {
+		return UnmarshalData(cont, f)
+	}
+
+	log.Infof("Unmarshaling CSV data with delimiter: %q", delim)
+	return UnmarshalCSV(cont, delim)
+}
+
+// KeyBy returns a list of maps as a map keyed by the value of a column. An empty key returns the data as is.
+func KeyBy(v interface{}, key string) (interface{}, error) {
+	if key == "" {
+		return v, nil
+	}
+
+	l, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Can't key %T by \"%s\", needs to be a list of maps", v, key)
+	}
+
+	r := make(map[string]interface{}, len(l))
+	for _, e := range l {
+		m, ok := e.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("Can't key rows, needs to be a list of maps")
+		}
+
+		k, ok := m[key]
+		if !ok {
+			return nil, fmt.Errorf("Key column \"%s\" doesn't exist", key)
+		}
+
+		s := fmt.Sprintf("%v", k)
+		if _, ok := r[s]; ok {
+			return nil, fmt.Errorf("Duplicate value \"%s\" for key column \"%s\"", s, key)
+		}
+		r[s] = m
+	}
+	return r, nil
+}
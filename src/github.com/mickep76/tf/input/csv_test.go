@@ -0,0 +1,67 @@
+package input
+
+import (
+	"testing"
+)
+
+func Test_UnmarshalCSV(t *testing.T) {
+	l, err := UnmarshalCSV([]byte("host,ip\nweb1,10.0.0.1\n\"db,1\",10.0.0.2\n"), ',')
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(l) != 2 || l[1].(map[string]interface{})["host"] != "db,1" {
+		t.Errorf("UnmarshalCSV didn't return expected result: %v", l)
+	} else {
+		t.Log("UnmarshalCSV test passes")
+	}
+
+	if l, err := UnmarshalCSV([]byte("host;ip\nweb1;10.0.0.1\n"), ';'); err != nil || l[0].(map[string]interface{})["ip"] != "10.0.0.1" {
+		t.Errorf("UnmarshalCSV didn't return expected result: %v %v", l, err)
+	} else {
+		t.Log("UnmarshalCSV test passes")
+	}
+
+	if _, err := UnmarshalCSV([]byte("host,ip\nweb1\n"), ','); err == nil {
+		t.Error("UnmarshalCSV didn't return expected error")
+	} else {
+		t.Log("UnmarshalCSV test passes")
+	}
+}
+
+func Test_KeyBy(t *testing.T) {
+	v, _ := UnmarshalData([]byte("host\tip\nweb1\t10.0.0.1\nweb2\t10.0.0.2\n"), TSV)
+
+	if m, err := KeyBy(v, "host"); err != nil || m.(map[string]interface{})["web2"].(map[string]interface{})["ip"] != "10.0.0.2" {
+		t.Errorf("KeyBy didn't return expected result: %v %v", m, err)
+	} else {
+		t.Log("KeyBy test passes")
+	}
+
+	if _, err := KeyBy(v, "missing"); err == nil {
+		t.Error("KeyBy didn't return expected error")
+	} else {
+		t.Log("KeyBy test passes")
+	}
+
+	v, _ = UnmarshalCSV([]byte("host\nweb1\nweb1\n"), ',')
+	if _, err := KeyBy(v, "host"); err == nil {
+		t.Error("KeyBy didn't return expected error for duplicate value")
+	} else {
+		t.Log("KeyBy test passes")
+	}
+}
+
+func Test_ParseDelimiter(t *testing.T) {
+	for s, e := range map[string]rune{";": ';', `\t`: '\t', "tab": '\t', "|": '|'} {
+		if r, err := ParseDelimiter(s); err != nil || r != e {
+			t.Errorf("ParseDelimiter didn't return expected result for: %q", s)
+		}
+	}
+
+	if _, err := ParseDelimiter(";;"); err == nil {
+		t.Error("ParseDelimiter didn't return expected error")
+	} else {
+		t.Log("ParseDelimiter test passes")
+	}
+}
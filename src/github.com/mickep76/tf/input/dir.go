@@ -0,0 +1,158 @@
+package input
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+type dirProvider struct{}
+
+func init() {
+	Register("dir", dirProvider{})
+}
+
+func (dirProvider) Namespace() string {
+	return ""
+}
+
+func (dirProvider) Options() []Option {
+	return []Option{
+		{Key: "path", Description: "Path to input directory", Required: true},
+		{Key: "include", Description: "Globs for files to include, defaults to all supported files", Type: OptList},
+		{Key: "exclude", Description: "Globs for files and directories to exclude", Type: OptList},
+	}
+}
+
+func (dirProvider) Get(opts Options, data map[string]interface{}) (interface{}, error) {
+	return GetDir(opts.String("path"), opts.List("include"), opts.List("exclude"), data)
+}
+
+// matchGlobs returns true if a relative path matches any of the globs. A glob without "/" matches the file name,
+// otherwise the relative path.
+func matchGlobs(rel string, globs []string) (bool, error) {
+	for _, g := range globs {
+		n := rel
+		if !strings.Contains(g, "/") {
+			n = path.Base(rel)
+		}
+
+		ok, err := path.Match(g, n)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GetDir loads all supported files in a directory tree using LoadFile. The relative path of a file without
+// extension is used as nested keys i.e. "db/primary.yaml" is returned as {"db": {"primary": ...}}. Hidden files
+// and directories are skipped. A file with a map of values is merged with a directory of the same name, any other
+// value returns an error.
+func GetDir(dir string, include []string, exclude []string, data map[string]interface{}) (map[string]interface{}, error) {
+	v := make(map[string]interface{})
+
+	log.Infof("Walking directory: %s", dir)
+	err := filepath.Walk(dir, func(fn string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, fn)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == "." {
+			return nil
+		}
+
+		if strings.HasPrefix(fi.Name(), ".") {
+			if fi.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		ok, err := matchGlobs(rel, exclude)
+		if err != nil {
+			return err
+		}
+		if ok {
+			log.Infof("Exclude: %s", rel)
+			if fi.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if fi.IsDir() {
+			return nil
+		}
+
+		if _, ok := FileDataFmt(fn); !ok {
+			log.Infof("Skip file with unsupported data format: %s", rel)
+			return nil
+		}
+
+		if len(include) > 0 {
+			ok, err := matchGlobs(rel, include)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return nil
+			}
+		}
+
+		f, err := LoadFile(fn, data)
+		if err != nil {
+			return err
+		}
+
+		// Remove the file extension, including ".tf" for templated files.
+		key := strings.TrimSuffix(rel, ".tf")
+		key = strings.TrimSuffix(key, path.Ext(key))
+
+		cur := v
+		keys := strings.Split(key, "/")
+		for i, k := range keys[:len(keys)-1] {
+			e, ok := cur[k]
+			if !ok {
+				e = make(map[string]interface{})
+				cur[k] = e
+			}
+
+			next, ok := e.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("Can't load file %s, key \"%s\" is both a map of values and a %T", rel, strings.Join(keys[:i+1], "/"), e)
+			}
+			cur = next
+		}
+
+		k := keys[len(keys)-1]
+		if e, ok := cur[k]; ok {
+			_, em := e.(map[string]interface{})
+			_, fm := f.(map[string]interface{})
+			if em != fm {
+				return fmt.Errorf("Can't load file %s, key \"%s\" is both a map of values and a value", rel, key)
+			}
+			cur[k] = DeepMerge(e, f, false)
+		} else {
+			cur[k] = f
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
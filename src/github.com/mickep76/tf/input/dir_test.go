@@ -0,0 +1,101 @@
+package input
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_GetDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tf")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	for fn, c := range map[string]string{
+		"db/primary.yaml":    "host: db1\n",
+		"db/replica.json":    `{"host": "db2"}`,
+		"web/site.yaml.tf":   "name: {{ .Site }}\n",
+		"web/README.md":      "# Not supported\n",
+		"web/secret.yaml":    "password: secret\n",
+		"tmp/scratch.yaml":   "a: 1\n",
+		".git/config.yaml":   "a: 1\n",
+		"db/backup/old.toml": "a = 1\n",
+	} {
+		p := filepath.Join(dir, filepath.FromSlash(fn))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err.Error())
+		}
+		if err := ioutil.WriteFile(p, []byte(c), 0644); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	v, err := GetDir(dir, nil, []string{"tmp", "secret.*", "db/backup"}, map[string]interface{}{"Site": "sto"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	db, _ := v["db"].(map[string]interface{})
+	web, _ := v["web"].(map[string]interface{})
+	if db["primary"].(map[string]interface{})["host"] != "db1" || db["replica"].(map[string]interface{})["host"] != "db2" ||
+		web["site"].(map[string]interface{})["name"] != "sto" {
+		t.Errorf("GetDir didn't return expected result: %v", v)
+	} else {
+		t.Log("GetDir test passes")
+	}
+
+	if _, ok := v["tmp"]; ok || web["secret"] != nil || db["backup"] != nil || v[".git"] != nil || web["README"] != nil {
+		t.Errorf("GetDir didn't exclude expected files: %v", v)
+	} else {
+		t.Log("GetDir test passes")
+	}
+
+	v, err = GetDir(dir, []string{"db/*.json"}, nil, nil)
+	if db, ok := v["db"].(map[string]interface{}); err != nil || !ok || len(v) != 1 || len(db) != 1 || db["replica"] == nil {
+		t.Errorf("GetDir didn't include expected files: %v %v", v, err)
+	} else {
+		t.Log("GetDir test passes")
+	}
+}
+
+func Test_GetDirConflict(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tf")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	for fn, c := range map[string]string{
+		"db.yaml":   "[db1, db2]\n",
+		"db/p.yaml": "host: db1\n",
+	} {
+		p := filepath.Join(dir, filepath.FromSlash(fn))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err.Error())
+		}
+		if err := ioutil.WriteFile(p, []byte(c), 0644); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	if _, err := GetDir(dir, nil, nil, nil); err == nil || !strings.Contains(err.Error(), `key "db"`) {
+		t.Errorf("GetDir didn't return expected error for a key that is both a map and a list: %v", err)
+	} else {
+		t.Log("GetDir test passes")
+	}
+
+	// A file with a map of values is merged with the directory.
+	if err := ioutil.WriteFile(filepath.Join(dir, "db.yaml"), []byte("replica: db2\n"), 0644); err != nil {
+		t.Fatal(err.Error())
+	}
+	v, err := GetDir(dir, nil, nil, nil)
+	if db, ok := v["db"].(map[string]interface{}); err != nil || !ok || db["replica"] != "db2" || db["p"] == nil {
+		t.Errorf("GetDir didn't return expected result: %v %v", v, err)
+	} else {
+		t.Log("GetDir test passes")
+	}
+}
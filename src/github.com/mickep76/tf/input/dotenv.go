@@ -0,0 +1,71 @@
+package input
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// UnmarshalDotenv unmarshal dotenv data as used by Docker and Compose, lines are KEY=value optionally prefixed
+// with "export". Double quoted values support \n, \t, \" and \\ escapes, single quoted values are literal and
+// unquoted values end at " #".
+func UnmarshalDotenv(cont []byte) (map[string]interface{}, error) {
+	v := make(map[string]interface{})
+
+	s := bufio.NewScanner(bytes.NewReader(cont))
+	for n := 1; s.Scan(); n++ {
+		l := strings.TrimSpace(s.Text())
+		if l == "" || l[0] == '#' {
+			continue
+		}
+		l = strings.TrimPrefix(l, "export ")
+
+		i := strings.Index(l, "=")
+		if i < 1 {
+			return nil, fmt.Errorf("Incorrect dotenv line %d: %s", n, l)
+		}
+		key, val := strings.TrimSpace(l[:i]), strings.TrimSpace(l[i+1:])
+
+		switch {
+		case strings.HasPrefix(val, `"`):
+			var b strings.Builder
+			j := 1
+			for ; j < len(val) && val[j] != '"'; j++ {
+				if val[j] == '\\' && j+1 < len(val) {
+					j++
+					switch val[j] {
+					case 'n':
+						b.WriteByte('\n')
+					case 't':
+						b.WriteByte('\t')
+					default:
+						b.WriteByte(val[j])
+					}
+					continue
+				}
+				b.WriteByte(val[j])
+			}
+			if j == len(val) {
+				return nil, fmt.Errorf("Missing closing quote on dotenv line %d: %s", n, l)
+			}
+			val = b.String()
+		case strings.HasPrefix(val, "'"):
+			j := strings.Index(val[1:], "'")
+			if j < 0 {
+				return nil, fmt.Errorf("Missing closing quote on dotenv line %d: %s", n, l)
+			}
+			val = val[1 : j+1]
+		default:
+			if j := strings.Index(val, " #"); j >= 0 {
+				val = strings.TrimSpace(val[:j])
+			}
+		}
+		v[key] = val
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
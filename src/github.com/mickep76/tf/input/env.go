@@ -0,0 +1,114 @@
+package input
+
+import (
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+type envProvider struct{}
+
+func init() {
+	Register("env", envProvider{})
+}
+
+func (envProvider) Namespace() string {
+	return "Env"
+}
+
+func (envProvider) Options() []Option {
+	return []Option{
+		{Key: "env_prefix", Description: "Env only include variables with prefix such as APP_"},
+		{Key: "env_strip_prefix", Description: "Env remove the prefix from variable names", Type: OptBool},
+		{Key: "env_separator", Description: "Env separator for nested keys such as __"},
+		{Key: "env_keep_case", Description: "Env keep the case of variable names, otherwise they are lowercased", Type: OptBool},
+		{Key: "env_decode", Description: "Env decode values that are maps or lists using a format such as JSON or YAML, other values are kept as strings"},
+	}
+}
+
+func (envProvider) Get(opts Options, data map[string]interface{}) (interface{}, error) {
+	var decode *DataFmt
+	if opts.IsSet("env_decode") {
+		f, err := ParseDataFmt(opts.String("env_decode"))
+		if err != nil {
+			return nil, err
+		}
+		decode = &f
+	}
+
+	return GetEnv(opts.String("env_prefix"), opts.Bool("env_strip_prefix"), opts.String("env_separator"),
+		opts.Bool("env_keep_case"), decode), nil
+}
+
+// GetEnv gets environment variables with a prefix. Names are split on the separator into nested maps i.e. with
+// the separator "__" APP__DB__HOST is returned as {"app": {"db": {"host": ...}}}. If decode isn't nil values
+// that are a map or a list when unmarshaled using the data format are decoded, other values are kept as strings.
+func GetEnv(prefix string, strip bool, sep string, keepCase bool, decode *DataFmt) map[string]interface{} {
+	var names []string
+	vals := make(map[string]string)
+	for _, e := range os.Environ() {
+		a := strings.SplitN(e, "=", 2)
+		if len(a) != 2 || !strings.HasPrefix(a[0], prefix) {
+			continue
+		}
+		names = append(names, a[0])
+		vals[a[0]] = a[1]
+	}
+	sort.Strings(names)
+
+	v := make(map[string]interface{})
+	for _, n := range names {
+		var val interface{} = vals[n]
+		if decode != nil && vals[n] != "" {
+			// Only use maps and lists that aren't empty, so values such as "on", "0123" or "#fff" which is an empty YAML
+			// document are kept as is.
+			if d, err := UnmarshalData([]byte(vals[n]), *decode); err == nil {
+				switch reflect.ValueOf(d).Kind() {
+				case reflect.Map, reflect.Slice:
+					if reflect.ValueOf(d).Len() > 0 {
+						val = d
+					}
+				}
+			}
+		}
+
+		key := n
+		if strip {
+			key = strings.TrimPrefix(key, prefix)
+		}
+		if !keepCase {
+			key = strings.ToLower(key)
+		}
+
+		keys := []string{key}
+		if sep != "" {
+			keys = strings.Split(strings.Trim(key, sep), sep)
+		}
+
+		cur := v
+		for i, k := range keys {
+			if i == len(keys)-1 {
+				if _, ok := cur[k].(map[string]interface{}); ok {
+					log.Warnf("Skip environment variable %s, it conflicts with nested keys", n)
+					break
+				}
+				cur[k] = val
+				break
+			}
+
+			next, ok := cur[k].(map[string]interface{})
+			if !ok {
+				if _, ok := cur[k]; ok {
+					log.Warnf("Environment variable %s replaces a value with nested keys", n)
+				}
+				next = make(map[string]interface{})
+				cur[k] = next
+			}
+			cur = next
+		}
+	}
+	return v
+}
@@ -0,0 +1,50 @@
+package input
+
+import (
+	"testing"
+)
+
+func Test_GetOSEnv(t *testing.T) {
+	t.Setenv("TF_TEST_URL", "http://example.com/?a=1&b=2")
+	if v := GetOSEnv(); v["TF_TEST_URL"] != "http://example.com/?a=1&b=2" {
+		t.Errorf("GetOSEnv didn't return expected value: %v", v["TF_TEST_URL"])
+	} else {
+		t.Log("GetOSEnv test passes")
+	}
+}
+
+func Test_GetEnv(t *testing.T) {
+	t.Setenv("TF_APP__DB__HOST", "db1")
+	t.Setenv("TF_APP__DB__PORT", "5432")
+	t.Setenv("TF_APP__HOSTS", `["web1", "web2"]`)
+	t.Setenv("TF_APP__DSN", "user=app password=a=b")
+	t.Setenv("TF_APP__COLOR", "#fff")
+	t.Setenv("TF_APP__DEBUG", "on")
+
+	v := GetEnv("TF_APP__", true, "__", false, nil)
+	db, ok := v["db"].(map[string]interface{})
+	if !ok || db["host"] != "db1" || db["port"] != "5432" || v["dsn"] != "user=app password=a=b" {
+		t.Errorf("GetEnv didn't return expected result: %v", v)
+	} else {
+		t.Log("GetEnv test passes")
+	}
+
+	f := JSON
+	v = GetEnv("TF_APP", false, "__", true, &f)
+	app, ok := v["TF_APP"].(map[string]interface{})
+	if !ok || len(app["HOSTS"].([]interface{})) != 2 || app["DB"].(map[string]interface{})["PORT"] != "5432" ||
+		app["DSN"] != "user=app password=a=b" {
+		t.Errorf("GetEnv didn't return expected decoded result: %v", v)
+	} else {
+		t.Log("GetEnv test passes")
+	}
+
+	f = YAML
+	v = GetEnv("TF_APP__", true, "__", false, &f)
+	if hosts, ok := v["hosts"].([]interface{}); !ok || len(hosts) != 2 || v["color"] != "#fff" || v["debug"] != "on" ||
+		v["dsn"] != "user=app password=a=b" {
+		t.Errorf("GetEnv didn't return expected YAML decoded result: %v", v)
+	} else {
+		t.Log("GetEnv test passes")
+	}
+}
@@ -1,20 +1,209 @@
 package input
 
 import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
 	"strings"
+	"time"
 
-	etcd "github.com/coreos/go-etcd/etcd"
+	log "github.com/Sirupsen/logrus"
 )
 
+type etcdProvider struct{}
+
+func init() {
+	Register("etcd", etcdProvider{})
+}
+
+func (etcdProvider) Namespace() string {
+	return "Etcd"
+}
+
+func (etcdProvider) Remote() bool {
+	return true
+}
+
+func (etcdProvider) Options() []Option {
+	return []Option{
+		{Key: "etcd_host", Description: "Etcd Host", Shared: true},
+		{Key: "etcd_port", Description: "Etcd Port", Type: OptInt, Default: int64(2379), Shared: true},
+		{Key: "etcd_scheme", Description: "Etcd scheme http or https", Default: "http", Shared: true},
+		{Key: "etcd_endpoints", Description: "Etcd endpoints to fail over between, replaces host, port and scheme", Type: OptList, Shared: true},
+		{Key: "etcd_ca", Description: "Etcd CA file for verifying the server certificate", Shared: true},
+		{Key: "etcd_cert", Description: "Etcd client certificate file", Shared: true},
+		{Key: "etcd_key", Description: "Etcd client key file", Shared: true},
+		{Key: "etcd_username", Description: "Etcd username", Shared: true},
+		{Key: "etcd_password", Description: "Etcd password", Shared: true},
+		{Key: "etcd_password_file", Description: "Etcd password file", Shared: true},
+		{Key: "etcd_dir", Description: "Etcd Dir", Default: "/"},
+		{Key: "etcd_version", Description: "Etcd API version 2 or 3", Type: OptInt, Default: int64(2), Shared: true},
+		{Key: "etcd_select", Description: "Etcd select a sub-tree of the input such as .hosts.web"},
+	}
+}
+
+func (etcdProvider) Get(opts Options, data map[string]interface{}) (interface{}, error) {
+	c := EtcdConfig{
+		Endpoints: opts.List("etcd_endpoints"),
+		Username:  opts.String("etcd_username"),
+		Password:  opts.String("etcd_password"),
+		Timeout:   opts.Duration("timeout"),
+	}
+
+	if len(c.Endpoints) == 0 {
+		if !opts.IsSet("etcd_host") {
+			return nil, errors.New("For input type \"etcd\" you need to specify \"etcd_host\" or \"etcd_endpoints\"")
+		}
+		c.Endpoints = []string{fmt.Sprintf("%s://%v:%v", opts.String("etcd_scheme"), opts.String("etcd_host"), opts.Int("etcd_port"))}
+	}
+
+	for _, e := range c.Endpoints {
+		u, err := url.Parse(e)
+		if err != nil {
+			return nil, err
+		}
+
+		switch u.Scheme {
+		case "http":
+		case "https":
+			if c.TLS == nil {
+				if c.TLS, err = TLSConfig(opts.String("etcd_ca"), opts.String("etcd_cert"), opts.String("etcd_key"), false); err != nil {
+					return nil, err
+				}
+			}
+		default:
+			return nil, fmt.Errorf("Unsupported Etcd scheme for endpoint: %s, needs to be http or https", e)
+		}
+	}
+
+	if opts.IsSet("etcd_password_file") {
+		var err error
+		if c.Password, err = readSecret(opts.String("etcd_password_file")); err != nil {
+			return nil, err
+		}
+	}
+
+	var v map[string]interface{}
+	var err error
+	switch opts.Int("etcd_version") {
+	case 2:
+		v, err = GetEtcd(c, opts.String("etcd_dir"))
+	case 3:
+		v, err = GetEtcdV3(c, opts.String("etcd_dir"))
+	default:
+		return nil, errors.New("Unsupported Etcd API version, needs to be 2 or 3")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return Select(v, opts.String("etcd_select"))
+}
+
+// EtcdConfig contains the settings for connecting to Etcd.
+type EtcdConfig struct {
+	Endpoints []string
+	TLS       *tls.Config
+	Username  string
+	Password  string
+	Timeout   time.Duration
+}
+
+// failover calls f for each endpoint until it succeeds or returns an error that isn't caused by the endpoint
+// being unavailable.
+func (c EtcdConfig) failover(f func(endpoint string) error) error {
+	var err error
+	for i, e := range c.Endpoints {
+		if err = f(e); err == nil {
+			log.Infof("Etcd request served by endpoint: %s", e)
+			return nil
+		}
+
+		if !etcdUnavailable(err) {
+			return err
+		}
+
+		if i < len(c.Endpoints)-1 {
+			log.Warnf("Etcd endpoint %s failed, trying next endpoint: %v", e, err)
+		}
+	}
+	return err
+}
+
+// etcdUnavailable returns true if an error means the endpoint couldn't serve the request.
+func etcdUnavailable(err error) bool {
+	if e, ok := err.(*statusError); ok {
+		return e.code >= 500
+	}
+	return true
+}
+
+// EtcdNode is a node returned by the Etcd v2 keys API.
+type EtcdNode struct {
+	Key   string
+	Value string
+	Dir   bool
+	Nodes []*EtcdNode
+}
+
+// GetEtcd gets a directory recursively using the Etcd v2 keys API.
+func GetEtcd(c EtcdConfig, dir string) (map[string]interface{}, error) {
+	client := &http.Client{Timeout: c.Timeout}
+	if c.TLS != nil {
+		client.Transport = &http.Transport{TLSClientConfig: c.TLS}
+	}
+
+	u := url.URL{Path: "/v2/keys/" + strings.TrimLeft(dir, "/"), RawQuery: "recursive=true&sorted=true"}
+
+	var body []byte
+	err := c.failover(func(endpoint string) error {
+		req, err := http.NewRequest("GET", strings.TrimRight(endpoint, "/")+u.String(), nil)
+		if err != nil {
+			return err
+		}
+		if c.Username != "" {
+			req.SetBasicAuth(c.Username, c.Password)
+		}
+
+		log.Infof("Get Etcd v2 keys: %s dir: %s", endpoint, dir)
+		r, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer r.Body.Close()
+
+		if body, err = ioutil.ReadAll(r.Body); err != nil {
+			return err
+		}
+		if r.StatusCode != http.StatusOK {
+			return &statusError{code: r.StatusCode, msg: fmt.Sprintf("Etcd request failed: %s: %s", r.Status, strings.TrimSpace(string(body)))}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var res struct {
+		Node EtcdNode
+	}
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, err
+	}
+	return EtcdMap(&res.Node), nil
+}
+
 // EtcdMap creates a nested data structure from a Etcd node.
-func EtcdMap(root *etcd.Node) map[string]interface{} {
+func EtcdMap(root *EtcdNode) map[string]interface{} {
 	v := make(map[string]interface{})
 
 	for _, n := range root.Nodes {
 		keys := strings.Split(n.Key, "/")
 		k := keys[len(keys)-1]
 		if n.Dir {
-			v[k] = make(map[string]interface{})
 			v[k] = EtcdMap(n)
 		} else {
 			v[k] = n.Value
@@ -22,3 +211,44 @@ func EtcdMap(root *etcd.Node) map[string]interface{} {
 	}
 	return v
 }
+
+// KVMap creates the same nested data structure as EtcdMap from slash separated keys below a prefix, a nil value
+// represents a directory.
+func KVMap(prefix string, kvs map[string]interface{}) map[string]interface{} {
+	v := make(map[string]interface{})
+	prefix = strings.Trim(prefix, "/")
+
+	for k, val := range kvs {
+		k = strings.Trim(k, "/")
+		if prefix != "" {
+			if k != prefix && !strings.HasPrefix(k, prefix+"/") {
+				continue
+			}
+			k = strings.TrimPrefix(strings.TrimPrefix(k, prefix), "/")
+		}
+		if k == "" {
+			continue
+		}
+
+		keys := strings.Split(k, "/")
+		m := v
+		for _, d := range keys[:len(keys)-1] {
+			sub, ok := m[d].(map[string]interface{})
+			if !ok {
+				sub = make(map[string]interface{})
+				m[d] = sub
+			}
+			m = sub
+		}
+
+		last := keys[len(keys)-1]
+		if val == nil {
+			if _, ok := m[last].(map[string]interface{}); !ok {
+				m[last] = make(map[string]interface{})
+			}
+		} else if _, ok := m[last].(map[string]interface{}); !ok {
+			m[last] = val
+		}
+	}
+	return v
+}
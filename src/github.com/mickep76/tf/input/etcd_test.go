@@ -0,0 +1,45 @@
+package input
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_GetEtcd(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if u, p, ok := r.BasicAuth(); !ok || u != "root" || p != "secret" {
+			http.Error(w, `{"errorCode":110,"message":"The request requires user authentication"}`, http.StatusUnauthorized)
+			return
+		}
+
+		if r.URL.Path != "/v2/keys/hosts" || r.URL.Query().Get("recursive") != "true" {
+			http.Error(w, `{"errorCode":100,"message":"Key not found"}`, http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"action": "get", "node": {"key": "/hosts", "dir": true, "nodes": [
+			{"key": "/hosts/host1.example.com", "dir": true, "nodes": [{"key": "/hosts/host1.example.com/serialno", "value": "abc123"}]},
+			{"key": "/hosts/host2.example.com", "dir": true, "nodes": [{"key": "/hosts/host2.example.com/serialno", "value": "def456"}]}
+		]}}`))
+	}))
+	defer ts.Close()
+
+	c := EtcdConfig{Endpoints: []string{ts.URL}, Username: "root", Password: "secret"}
+	v, err := GetEtcd(c, "/hosts")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	h2, _ := v["host2.example.com"].(map[string]interface{})
+	if len(v) != 2 || h2["serialno"] != "def456" {
+		t.Errorf("GetEtcd didn't return expected result: %v", v)
+	} else {
+		t.Log("GetEtcd test passes")
+	}
+
+	if _, err := GetEtcd(c, "/missing"); err == nil {
+		t.Error("GetEtcd should fail for a missing directory")
+	} else if etcdUnavailable(err) {
+		t.Errorf("GetEtcd shouldn't fail over for a missing directory: %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+package input
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// etcdV3RangeEnd returns the range end for getting all keys with a prefix.
+func etcdV3RangeEnd(prefix string) string {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return string(end[:i+1])
+		}
+	}
+	// No range end exists, use "\x00" to get all keys from the prefix.
+	return "\x00"
+}
+
+// etcdV3Post posts a JSON request to the Etcd v3 JSON gateway.
+func etcdV3Post(client *http.Client, u string, token string, body []byte) ([]byte, error) {
+	req, err := http.NewRequest("POST", u, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", token)
+	}
+
+	r, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	if r.StatusCode != http.StatusOK {
+		return nil, &statusError{code: r.StatusCode, msg: fmt.Sprintf("Etcd request failed: %s: %s", r.Status, strings.TrimSpace(string(b)))}
+	}
+	return b, nil
+}
+
+// etcdV3Authenticate gets an authentication token for a user.
+func etcdV3Authenticate(client *http.Client, endpoint string, c EtcdConfig) (string, error) {
+	req, err := json.Marshal(map[string]string{"name": c.Username, "password": c.Password})
+	if err != nil {
+		return "", err
+	}
+
+	log.Infof("Authenticate to Etcd as: %s", c.Username)
+	body, err := etcdV3Post(client, endpoint+"/v3/auth/authenticate", "", req)
+	if err != nil {
+		return "", err
+	}
+
+	var res struct {
+		Token string
+	}
+	if err := json.Unmarshal(body, &res); err != nil {
+		return "", err
+	}
+	return res.Token, nil
+}
+
+// GetEtcdV3 gets all keys below a directory using the Etcd v3 JSON gateway. Keys are split on "/" into the same
+// nested data structure as EtcdMap.
+func GetEtcdV3(c EtcdConfig, dir string) (map[string]interface{}, error) {
+	client := &http.Client{Timeout: c.Timeout}
+	if c.TLS != nil {
+		client.Transport = &http.Transport{TLSClientConfig: c.TLS}
+	}
+
+	// Get all keys for the root directory.
+	key, end := "\x00", "\x00"
+	if strings.Trim(dir, "/") != "" {
+		key = strings.TrimRight(dir, "/") + "/"
+		end = etcdV3RangeEnd(key)
+	}
+
+	req, err := json.Marshal(map[string]string{
+		"key":       base64.StdEncoding.EncodeToString([]byte(key)),
+		"range_end": base64.StdEncoding.EncodeToString([]byte(end)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var body []byte
+	err = c.failover(func(endpoint string) error {
+		var token string
+		if c.Username != "" {
+			var err error
+			if token, err = etcdV3Authenticate(client, endpoint, c); err != nil {
+				return err
+			}
+		}
+
+		log.Infof("Get Etcd v3 range: %s prefix: %s", endpoint, key)
+		var err error
+		body, err = etcdV3Post(client, endpoint+"/v3/kv/range", token, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var res struct {
+		Kvs []struct {
+			Key   string
+			Value string
+		}
+	}
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, err
+	}
+
+	kvs := make(map[string]interface{})
+	for _, kv := range res.Kvs {
+		k, err := base64.StdEncoding.DecodeString(kv.Key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := base64.StdEncoding.DecodeString(kv.Value)
+		if err != nil {
+			return nil, err
+		}
+		kvs[string(k)] = string(v)
+	}
+
+	return KVMap(dir, kvs), nil
+}
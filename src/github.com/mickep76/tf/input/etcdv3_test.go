@@ -0,0 +1,59 @@
+package input
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_GetEtcdV3(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		if json.NewDecoder(r.Body).Decode(&req) != nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch {
+		case r.URL.Path == "/v3/auth/authenticate" && req["name"] == "root" && req["password"] == "secret":
+			w.Write([]byte(`{"token": "abc.123"}`))
+			return
+		case r.URL.Path != "/v3/kv/range" || r.Header.Get("Authorization") != "abc.123":
+			http.Error(w, "permission denied", http.StatusUnauthorized)
+			return
+		}
+
+		// Key "/hosts/" and range end "/hosts0" base64 encoded.
+		if req["key"] != "L2hvc3RzLw==" || req["range_end"] != "L2hvc3RzMA==" {
+			t.Errorf("GetEtcdV3 didn't request expected range: %v", req)
+		}
+		w.Write([]byte(`{"kvs": [
+			{"key": "L2hvc3RzL2hvc3QxLmV4YW1wbGUuY29tL3NlcmlhbG5v", "value": "YWJjMTIz"},
+			{"key": "L2hvc3RzL2hvc3QyLmV4YW1wbGUuY29tL3NlcmlhbG5v", "value": "ZGVmNDU2"}
+		], "count": "2"}`))
+	}))
+	defer ts.Close()
+
+	// The first endpoint isn't listening and should fail over to the test server.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	down := "http://" + ln.Addr().String()
+	ln.Close()
+
+	c := EtcdConfig{Endpoints: []string{down, ts.URL}, Username: "root", Password: "secret"}
+	v, err := GetEtcdV3(c, "/hosts")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	h2, _ := v["host2.example.com"].(map[string]interface{})
+	if len(v) != 2 || h2["serialno"] != "def456" {
+		t.Errorf("GetEtcdV3 didn't return expected result: %v", v)
+	} else {
+		t.Log("GetEtcdV3 test passes")
+	}
+}
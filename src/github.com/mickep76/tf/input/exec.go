@@ -0,0 +1,100 @@
+package input
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+type execProvider struct{}
+
+func init() {
+	Register("exec", execProvider{})
+}
+
+func (execProvider) Namespace() string {
+	return "Exec"
+}
+
+func (execProvider) Options() []Option {
+	return []Option{
+		{Key: "exec_command", Description: "Exec command to run", Required: true},
+		{Key: "exec_args", Description: "Exec command argument, can be repeated", Type: OptArgs},
+		{Key: "exec_env", Description: "Exec environment variables as name:value, added to the current environment", Type: OptMap},
+		{Key: "exec_timeout", Description: "Exec timeout, 0 for no timeout", Type: OptDuration, Default: 30 * time.Second, Shared: true},
+		{Key: "exec_format", Description: "Exec output format same as --input-format or LINES for a list of lines", Default: "YAML"},
+	}
+}
+
+func (execProvider) Get(opts Options, data map[string]interface{}) (interface{}, error) {
+	var f *DataFmt
+	if opts.String("exec_format") != "LINES" {
+		df, err := ParseDataFmt(opts.String("exec_format"))
+		if err != nil {
+			return nil, err
+		}
+		f = &df
+	}
+
+	return GetExec(opts.String("exec_command"), opts.List("exec_args"), opts.Map("exec_env"), opts.Duration("exec_timeout"), f)
+}
+
+// execWaitDelay is how long to wait for the output to be closed after a command is killed.
+const execWaitDelay = 100 * time.Millisecond
+
+// GetExec runs a command and unmarshal the output. If the data format is nil the output is returned as a list of
+// lines. A timeout of 0 means no timeout.
+func GetExec(command string, args []string, env map[string]string, timeout time.Duration, f *DataFmt) (interface{}, error) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, command, args...)
+	// Don't wait for the output of child processes that are still running after the command is killed.
+	cmd.WaitDelay = execWaitDelay
+	cmd.Env = os.Environ()
+	for k, v := range env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	log.Infof("Run command: %s %s", command, strings.Join(args, " "))
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("Command %s timed out after %v", command, timeout)
+		}
+
+		msg := strings.TrimSpace(stderr.String())
+		if len(msg) > maxErrorBody {
+			msg = msg[:maxErrorBody] + "..."
+		}
+		if msg == "" {
+			return nil, fmt.Errorf("Command %s failed: %v", command, err)
+		}
+		return nil, fmt.Errorf("Command %s failed: %v: %s", command, err, msg)
+	}
+
+	if f != nil {
+		return UnmarshalData(stdout.Bytes(), *f)
+	}
+
+	l := []interface{}{}
+	s := bufio.NewScanner(&stdout)
+	for s.Scan() {
+		l = append(l, s.Text())
+	}
+	return l, s.Err()
+}
@@ -0,0 +1,47 @@
+package input
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_GetExec(t *testing.T) {
+	f := JSON
+	v, err := GetExec("sh", []string{"-c", `echo "{\"host\": \"$TF_HOST\"}"`}, map[string]string{"TF_HOST": "web1"}, time.Second, &f)
+	if m, ok := v.(map[string]interface{}); err != nil || !ok || m["host"] != "web1" {
+		t.Errorf("GetExec didn't return expected result: %v %v", v, err)
+	} else {
+		t.Log("GetExec test passes")
+	}
+
+	v, err = GetExec("sh", []string{"-c", "echo a; echo b"}, nil, 0, nil)
+	if l, ok := v.([]interface{}); err != nil || !ok || len(l) != 2 || l[1] != "b" {
+		t.Errorf("GetExec didn't return expected lines: %v %v", v, err)
+	} else {
+		t.Log("GetExec test passes")
+	}
+
+	if _, err := GetExec("sh", []string{"-c", "echo failed >&2; exit 3"}, nil, time.Second, nil); err == nil ||
+		!strings.Contains(err.Error(), "exit status 3: failed") {
+		t.Errorf("GetExec didn't return expected error: %v", err)
+	} else {
+		t.Log("GetExec test passes")
+	}
+
+	if _, err := GetExec("sleep", []string{"5"}, nil, 50*time.Millisecond, nil); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("GetExec didn't return expected timeout error: %v", err)
+	} else {
+		t.Log("GetExec test passes")
+	}
+
+	// The shell is killed on timeout but the sleep it started still holds on to the output.
+	start := time.Now()
+	if _, err := GetExec("sh", []string{"-c", "sleep 5; echo a"}, nil, 200*time.Millisecond, nil); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("GetExec didn't return expected timeout error: %v", err)
+	} else if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("GetExec didn't return after the timeout, took %v", d)
+	} else {
+		t.Log("GetExec test passes")
+	}
+}
@@ -0,0 +1,55 @@
+package input
+
+type fileProvider struct{}
+
+func init() {
+	Register("file", fileProvider{})
+}
+
+func (fileProvider) Namespace() string {
+	return ""
+}
+
+func (fileProvider) Options() []Option {
+	return []Option{
+		{Key: "path", Description: "Path to input file", Required: true},
+		{Key: "format", Description: "Format same as --input-format, defaults to using the file extension"},
+		{Key: "select", Description: "Select a sub-tree of the input such as .data.items"},
+		{Key: "csv_delimiter", Description: "CSV delimiter, defaults to \",\" for CSV and tab for TSV"},
+		{Key: "csv_key", Description: "CSV column to key rows by, returns a map instead of a list"},
+	}
+}
+
+func (fileProvider) Get(opts Options, data map[string]interface{}) (interface{}, error) {
+	var delim rune
+	if opts.IsSet("csv_delimiter") {
+		var err error
+		if delim, err = ParseDelimiter(opts.String("csv_delimiter")); err != nil {
+			return nil, err
+		}
+	}
+
+	var format *DataFmt
+	if opts.IsSet("format") {
+		f, err := ParseDataFmt(opts.String("format"))
+		if err != nil {
+			return nil, err
+		}
+		format = &f
+	}
+
+	b, f, err := ReadFile(opts.String("path"), format, data)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := UnmarshalDataDelim(b, f, delim)
+	if err != nil {
+		return nil, err
+	}
+
+	if v, err = KeyBy(v, opts.String("csv_key")); err != nil {
+		return nil, err
+	}
+	return Select(v, opts.String("select"))
+}
@@ -0,0 +1,132 @@
+package input
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+type gitProvider struct{}
+
+func init() {
+	Register("git", gitProvider{})
+}
+
+func (gitProvider) Namespace() string {
+	return "Git"
+}
+
+func (gitProvider) Options() []Option {
+	return []Option{
+		{Key: "git_repo", Description: "Git repository, path to the root of a local repository", Required: true, Shared: true},
+		{Key: "git_ref", Description: "Git ref such as a branch, tag or commit", Default: "HEAD"},
+		{Key: "git_path", Description: "Git path to file in the repository", Required: true},
+		{Key: "git_format", Description: "Git format same as --input-format, defaults to using the file extension"},
+	}
+}
+
+func (gitProvider) Get(opts Options, data map[string]interface{}) (interface{}, error) {
+	var format *DataFmt
+	if opts.IsSet("git_format") {
+		f, err := ParseDataFmt(opts.String("git_format"))
+		if err != nil {
+			return nil, err
+		}
+		format = &f
+	}
+
+	sha, c, err := GetGit(opts.String("git_repo"), opts.String("git_ref"), opts.String("git_path"))
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := LoadBytes(opts.String("git_path"), c, format, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"sha":  sha,
+		"ref":  opts.String("git_ref"),
+		"path": opts.String("git_path"),
+		"data": v,
+	}, nil
+}
+
+// git runs a git command, errors include the output from stderr.
+func git(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	log.Infof("Run git %s", strings.Join(args, " "))
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("Git %s failed: %v: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// gitRoot returns the root of the repository for a directory, the git directory for a bare repository.
+func gitRoot(dir string) (string, error) {
+	b, err := git(dir, "rev-parse", "--is-bare-repository")
+	if err != nil {
+		return "", err
+	}
+
+	arg := "--show-toplevel"
+	if strings.TrimSpace(string(b)) == "true" {
+		arg = "--absolute-git-dir"
+	}
+
+	if b, err = git(dir, "rev-parse", arg); err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(strings.TrimSpace(string(b)))
+}
+
+// GetGit gets the content of a file in a local git repository at a ref and the resolved commit SHA. The repository
+// needs to be the root of the repository, not a sub-directory.
+func GetGit(repo string, ref string, path string) (string, []byte, error) {
+	if strings.HasPrefix(repo, "-") || strings.HasPrefix(ref, "-") {
+		return "", nil, fmt.Errorf("Incorrect git repository %s or ref %s, can't start with \"-\"", repo, ref)
+	}
+
+	if fi, err := os.Stat(repo); err != nil || !fi.IsDir() {
+		return "", nil, fmt.Errorf("Git repository %s isn't a local directory", repo)
+	}
+
+	dir, err := filepath.Abs(repo)
+	if err != nil {
+		return "", nil, err
+	}
+	if dir, err = filepath.EvalSymlinks(dir); err != nil {
+		return "", nil, err
+	}
+
+	// Don't use a parent repository for a directory that isn't a repository.
+	root, err := gitRoot(dir)
+	if err != nil || root != dir {
+		return "", nil, fmt.Errorf("Git repository %s isn't the root of a repository", repo)
+	}
+
+	b, err := git(dir, "rev-parse", "--verify", "--quiet", ref+"^{commit}")
+	if err != nil {
+		return "", nil, fmt.Errorf("Unknown git ref %s in repository %s: %v", ref, repo, err)
+	}
+	sha := strings.TrimSpace(string(b))
+
+	log.Infof("Read git file: %s at: %s", path, sha)
+	c, err := git(dir, "cat-file", "blob", sha+":"+strings.TrimPrefix(path, "/"))
+	if err != nil {
+		return "", nil, err
+	}
+	return sha, c, nil
+}
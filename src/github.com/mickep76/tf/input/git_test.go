@@ -0,0 +1,91 @@
+package input
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func Test_GetGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git isn't installed")
+	}
+
+	dir, err := ioutil.TempDir("", "tf")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	run := func(args ...string) string {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=tf", "-c", "user.email=tf@example.com"}, args...)...)
+		cmd.Dir = dir
+		b, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, b)
+		}
+		return strings.TrimSpace(string(b))
+	}
+
+	run("init", "--quiet")
+	for i, c := range []string{"replicas: 1\n", "replicas: 2\n"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, "app.yaml"), []byte(c), 0644); err != nil {
+			t.Fatal(err.Error())
+		}
+		run("add", "app.yaml")
+		run("commit", "--quiet", "-m", "Commit")
+		if i == 0 {
+			run("tag", "v1")
+		}
+	}
+	head := run("rev-parse", "HEAD")
+
+	opts := NewOptions(gitProvider{})
+	opts["git_repo"] = dir
+	opts["git_path"] = "app.yaml"
+	v, err := gitProvider{}.Get(opts, nil)
+	if m, ok := v.(map[string]interface{}); err != nil || !ok || m["sha"] != head || m["data"].(map[string]interface{})["replicas"] != 2 {
+		t.Errorf("GetGit didn't return expected result: %v %v", v, err)
+	} else {
+		t.Log("GetGit test passes")
+	}
+
+	sha, c, err := GetGit(dir, "v1", "app.yaml")
+	if err != nil || sha == head || string(c) != "replicas: 1\n" {
+		t.Errorf("GetGit didn't return expected result for tag: %s %q %v", sha, c, err)
+	} else {
+		t.Log("GetGit test passes")
+	}
+
+	if _, _, err := GetGit(dir, "missing", "app.yaml"); err == nil || !strings.Contains(err.Error(), "Unknown git ref") {
+		t.Errorf("GetGit didn't return expected error: %v", err)
+	} else {
+		t.Log("GetGit test passes")
+	}
+
+	if _, _, err := GetGit(dir, "--output=x", "app.yaml"); err == nil || !strings.Contains(err.Error(), "can't start with") {
+		t.Errorf("GetGit didn't return expected error for ref starting with \"-\": %v", err)
+	} else {
+		t.Log("GetGit test passes")
+	}
+
+	// A sub-directory isn't the root of the repository.
+	sub := filepath.Join(dir, "sub")
+	if err := os.Mkdir(sub, 0755); err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, _, err := GetGit(sub, "HEAD", "app.yaml"); err == nil || !strings.Contains(err.Error(), "isn't the root") {
+		t.Errorf("GetGit didn't return expected error for a sub-directory: %v", err)
+	} else {
+		t.Log("GetGit test passes")
+	}
+
+	if _, _, err := GetGit("file://"+dir, "HEAD", "app.yaml"); err == nil {
+		t.Error("GetGit didn't return expected error for an url")
+	} else {
+		t.Log("GetGit test passes")
+	}
+}
@@ -0,0 +1,103 @@
+package input
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+)
+
+// hclValue converts a HCL value.
+func hclValue(n ast.Node) (interface{}, error) {
+	switch t := n.(type) {
+	case *ast.LiteralType:
+		return t.Token.Value(), nil
+	case *ast.ListType:
+		l := make([]interface{}, len(t.List))
+		for i, e := range t.List {
+			var err error
+			if l[i], err = hclValue(e); err != nil {
+				return nil, err
+			}
+		}
+		return l, nil
+	case *ast.ObjectType:
+		return hclObject(t.List)
+	}
+	return nil, fmt.Errorf("Unsupported HCL value at %s", n.Pos())
+}
+
+// hclObject converts a HCL object, blocks with labels such as `service "web" {}` become nested maps and repeated
+// blocks such as `rule {}` become a list of maps. Any other duplicate key returns an error.
+func hclObject(l *ast.ObjectList) (map[string]interface{}, error) {
+	m := make(map[string]interface{})
+
+	// Key paths of maps created for labels and of lists created for repeated blocks.
+	labels := make(map[string]bool)
+	lists := make(map[string]bool)
+
+	for _, item := range l.Items {
+		v, err := hclValue(item.Val)
+		if err != nil {
+			return nil, err
+		}
+
+		cur := m
+		p := ""
+		for i, k := range item.Keys {
+			key := fmt.Sprintf("%v", k.Token.Value())
+			p += strconv.Quote(key)
+			e, ok := cur[key]
+
+			if i < len(item.Keys)-1 {
+				if !ok {
+					e = make(map[string]interface{})
+					cur[key] = e
+					labels[p] = true
+				} else if !labels[p] {
+					return nil, fmt.Errorf("Duplicate HCL key \"%s\" at %s", key, item.Pos())
+				}
+				cur = e.(map[string]interface{})
+				continue
+			}
+
+			_, block := v.(map[string]interface{})
+			_, prev := e.(map[string]interface{})
+			switch {
+			case !ok:
+				cur[key] = v
+			case block && lists[p]:
+				cur[key] = append(e.([]interface{}), v)
+			case block && prev && !labels[p]:
+				cur[key] = []interface{}{e, v}
+				lists[p] = true
+			default:
+				return nil, fmt.Errorf("Duplicate HCL key \"%s\" at %s", key, item.Pos())
+			}
+		}
+	}
+	return m, nil
+}
+
+// UnmarshalHCL unmarshal HCL data such as .hcl and .tfvars files. Blocks are returned as nested maps and repeated
+// blocks as a list of maps, the same way as TOML tables and arrays of tables.
+func UnmarshalHCL(cont []byte) (v map[string]interface{}, err error) {
+	f, err := hcl.ParseBytes(cont)
+	if err != nil {
+		return nil, err
+	}
+
+	// Token values panic on numbers that can't be parsed, such as integers that overflow.
+	defer func() {
+		if r := recover(); r != nil {
+			v, err = nil, fmt.Errorf("Incorrect HCL value: %v", r)
+		}
+	}()
+
+	l, ok := f.Node.(*ast.ObjectList)
+	if !ok {
+		return nil, fmt.Errorf("Unsupported HCL root at %s", f.Node.Pos())
+	}
+	return hclObject(l)
+}
@@ -0,0 +1,90 @@
+package input
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_UnmarshalHCL(t *testing.T) {
+	v, err := UnmarshalHCL([]byte(`
+region = "eu-west-1"
+replicas = 3
+tags = ["web", "prod"]
+
+db {
+  host = "db1"
+  port = 5432
+}
+
+service "web" {
+  port = 80
+}
+
+service "api" {
+  port = 8080
+}
+
+hosts = [{ name = "web1" }, { name = "web2" }]
+
+rule {
+  x = 1
+}
+
+rule {
+  x = 2
+}
+
+rule {
+  x = 3
+}
+`))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if v["region"] != "eu-west-1" || v["replicas"] != int64(3) || len(v["tags"].([]interface{})) != 2 {
+		t.Errorf("UnmarshalHCL didn't return expected values: %v", v)
+	} else {
+		t.Log("UnmarshalHCL test passes")
+	}
+
+	if db, ok := v["db"].(map[string]interface{}); !ok || db["host"] != "db1" {
+		t.Errorf("UnmarshalHCL didn't return block as a map: %v", v["db"])
+	} else {
+		t.Log("UnmarshalHCL test passes")
+	}
+
+	if s, ok := v["service"].(map[string]interface{}); !ok || s["api"].(map[string]interface{})["port"] != int64(8080) ||
+		s["web"].(map[string]interface{})["port"] != int64(80) {
+		t.Errorf("UnmarshalHCL didn't return labeled blocks as nested maps: %v", v["service"])
+	} else {
+		t.Log("UnmarshalHCL test passes")
+	}
+
+	if h, ok := v["hosts"].([]interface{}); !ok || h[1].(map[string]interface{})["name"] != "web2" {
+		t.Errorf("UnmarshalHCL didn't return list of objects: %v", v["hosts"])
+	} else {
+		t.Log("UnmarshalHCL test passes")
+	}
+
+	if r, ok := v["rule"].([]interface{}); !ok || len(r) != 3 || r[0].(map[string]interface{})["x"] != int64(1) ||
+		r[2].(map[string]interface{})["x"] != int64(3) {
+		t.Errorf("UnmarshalHCL didn't return repeated blocks as a list: %v", v["rule"])
+	} else {
+		t.Log("UnmarshalHCL test passes")
+	}
+
+	for _, c := range []string{"a = 1\na = 2\n", "service \"web\" {}\nservice {}\n", "db {}\ndb \"x\" {}\n"} {
+		if _, err := UnmarshalHCL([]byte(c)); err == nil || !strings.Contains(err.Error(), "Duplicate HCL key") {
+			t.Errorf("UnmarshalHCL didn't return expected error for duplicate keys: %q %v", c, err)
+		} else {
+			t.Log("UnmarshalHCL test passes")
+		}
+	}
+
+	if _, err := UnmarshalHCL([]byte("db {\n  host = ")); err == nil {
+		t.Error("UnmarshalHCL didn't return expected error")
+	} else {
+		t.Log("UnmarshalHCL test passes")
+	}
+}
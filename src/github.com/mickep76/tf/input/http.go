@@ -0,0 +1,291 @@
+package input
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+
+	"github.com/mickep76/tf/template"
+)
+
+type httpProvider struct{}
+
+func init() {
+	Register("http", httpProvider{})
+}
+
+func (httpProvider) Namespace() string {
+	return "HTTP"
+}
+
+func (httpProvider) Remote() bool {
+	return true
+}
+
+// Idempotent returns true for GET, HEAD and OPTIONS requests unless retrying other methods is enabled.
+func (httpProvider) Idempotent(opts Options) bool {
+	switch strings.ToUpper(opts.String("http_method")) {
+	case "GET", "HEAD", "OPTIONS":
+		return true
+	}
+	return opts.Bool("http_retry_non_idempotent")
+}
+
+func (httpProvider) Options() []Option {
+	return []Option{
+		{Key: "http_url", Description: "HTTP Url", Required: true},
+		{Key: "http_method", Description: "HTTP Method", Default: "GET"},
+		{Key: "http_retry_non_idempotent", Description: "HTTP Retry methods other than GET, HEAD and OPTIONS such as POST", Type: OptBool},
+		{Key: "http_header", Description: "HTTP Header", Default: "Accept: application/json", Shared: true},
+		{Key: "http_headers", Description: "HTTP Headers as name:value", Type: OptMap, Shared: true},
+		{Key: "http_query", Description: "HTTP Query parameters as name:value", Type: OptMap},
+		{Key: "http_username", Description: "HTTP Basic auth username", Shared: true},
+		{Key: "http_password", Description: "HTTP Basic auth password", Shared: true},
+		{Key: "http_password_file", Description: "HTTP Basic auth password file", Shared: true},
+		{Key: "http_token_file", Description: "HTTP Bearer token file", Shared: true},
+		{Key: "http_token_env", Description: "HTTP Bearer token environment variable", Shared: true},
+		{Key: "http_body", Description: "HTTP Request body, templated using the input data"},
+		{Key: "http_body_file", Description: "HTTP Request body file, templated using the input data"},
+		{Key: "http_format", Description: "HTTP Format same as --input-format, defaults to using the response Content-Type", Shared: true},
+		{Key: "http_allowed_status", Description: "HTTP Status codes allowed in addition to 2xx", Type: OptList},
+		{Key: "http_ca", Description: "HTTP CA file for verifying the server certificate", Shared: true},
+		{Key: "http_cert", Description: "HTTP Client certificate file", Shared: true},
+		{Key: "http_key", Description: "HTTP Client key file", Shared: true},
+		{Key: "http_insecure", Description: "HTTP Skip verifying the server certificate", Type: OptBool, Shared: true},
+		{Key: "http_proxy", Description: "HTTP Proxy url, defaults to using the environment", Shared: true},
+		{Key: "http_select", Description: "HTTP Select a sub-tree of the response such as .data.items"},
+		{Key: "http_csv_delimiter", Description: "HTTP CSV delimiter, defaults to \",\" for CSV and tab for TSV"},
+		{Key: "http_csv_key", Description: "HTTP CSV column to key rows by, returns a map instead of a list"},
+	}
+}
+
+func (httpProvider) Get(opts Options, data map[string]interface{}) (interface{}, error) {
+	var err error
+	r := HTTPRequest{
+		URL:      opts.String("http_url"),
+		Method:   strings.ToUpper(opts.String("http_method")),
+		Headers:  make(map[string]string),
+		Query:    opts.Map("http_query"),
+		Username: opts.String("http_username"),
+		Password: opts.String("http_password"),
+		Timeout:  opts.Duration("timeout"),
+	}
+
+	if r.TLS, err = TLSConfig(opts.String("http_ca"), opts.String("http_cert"), opts.String("http_key"), opts.Bool("http_insecure")); err != nil {
+		return nil, err
+	}
+
+	if opts.IsSet("http_proxy") {
+		if r.Proxy, err = url.Parse(opts.String("http_proxy")); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.IsSet("http_format") {
+		f, err := ParseDataFmt(opts.String("http_format"))
+		if err != nil {
+			return nil, err
+		}
+		r.Format = &f
+	}
+
+	if opts.IsSet("http_csv_delimiter") {
+		if r.Delimiter, err = ParseDelimiter(opts.String("http_csv_delimiter")); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, s := range opts.List("http_allowed_status") {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, errors.New("Incorrect HTTP status code: " + s)
+		}
+		r.AllowedStatus = append(r.AllowedStatus, n)
+	}
+
+	if h := opts.String("http_header"); h != "" {
+		kv := strings.SplitN(h, ":", 2)
+		if len(kv) != 2 {
+			return nil, errors.New("Incorrect HTTP header, needs to be name:value: " + h)
+		}
+		r.Headers[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	for k, v := range opts.Map("http_headers") {
+		r.Headers[k] = v
+	}
+
+	if opts.IsSet("http_password_file") {
+		if r.Password, err = readSecret(opts.String("http_password_file")); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.IsSet("http_token_file") {
+		if r.Token, err = readSecret(opts.String("http_token_file")); err != nil {
+			return nil, err
+		}
+	} else if opts.IsSet("http_token_env") {
+		var ok bool
+		if r.Token, ok = os.LookupEnv(opts.String("http_token_env")); !ok {
+			return nil, errors.New("Environment variable for HTTP token isn't set: " + opts.String("http_token_env"))
+		}
+	}
+
+	body := opts.String("http_body")
+	if opts.IsSet("http_body_file") {
+		c, err := ioutil.ReadFile(opts.String("http_body_file"))
+		if err != nil {
+			return nil, err
+		}
+		body = string(c)
+	}
+	if body != "" {
+		buf, err := template.Compile(body, data)
+		if err != nil {
+			return nil, err
+		}
+		r.Body = buf.String()
+	}
+
+	v, err := GetHTTP(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if v, err = KeyBy(v, opts.String("http_csv_key")); err != nil {
+		return nil, err
+	}
+	return Select(v, opts.String("http_select"))
+}
+
+// HTTPRequest contains the settings for a HTTP request.
+type HTTPRequest struct {
+	URL      string
+	Method   string
+	Headers  map[string]string
+	Query    map[string]string
+	Username string
+	Password string
+	Token    string
+	Body     string
+	// Format of the response, if nil it's determined by the response Content-Type.
+	Format *DataFmt
+	// Delimiter for CSV and TSV, if 0 the default for the format is used.
+	Delimiter rune
+	// AllowedStatus are status codes allowed in addition to 2xx.
+	AllowedStatus []int
+	TLS           *tls.Config
+	// Proxy url, if nil the proxy is taken from the environment.
+	Proxy   *url.URL
+	Timeout time.Duration
+}
+
+// maxErrorBody is the max. length of the response body included in errors.
+const maxErrorBody = 512
+
+// GetHTTP requests a HTTP url.
+func GetHTTP(hr HTTPRequest) (interface{}, error) {
+	u, err := url.Parse(hr.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(hr.Query) > 0 {
+		q := u.Query()
+		for k, v := range hr.Query {
+			q.Set(k, v)
+		}
+		u.RawQuery = q.Encode()
+	}
+
+	method := hr.Method
+	if method == "" {
+		method = "GET"
+	}
+
+	tr := &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: hr.TLS,
+	}
+	if hr.Proxy != nil {
+		log.Infof("Using HTTP proxy: %s", hr.Proxy.String())
+		tr.Proxy = http.ProxyURL(hr.Proxy)
+	}
+
+	client := &http.Client{Transport: tr, Timeout: hr.Timeout}
+	req, err := http.NewRequest(method, u.String(), strings.NewReader(hr.Body))
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range hr.Headers {
+		req.Header.Set(k, v)
+	}
+
+	if hr.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+hr.Token)
+	} else if hr.Username != "" {
+		req.SetBasicAuth(hr.Username, hr.Password)
+	}
+
+	log.Infof("HTTP request: %s %s", method, u.String())
+	r, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer r.Body.Close()
+	body, err2 := ioutil.ReadAll(r.Body)
+	if err2 != nil {
+		return nil, err2
+	}
+
+	if !hr.allowed(r.StatusCode) {
+		b := strings.TrimSpace(string(body))
+		if len(b) > maxErrorBody {
+			b = b[:maxErrorBody] + "..."
+		}
+		return nil, &statusError{code: r.StatusCode, msg: fmt.Sprintf("HTTP request failed: %s %s: %s: %s", method, u.String(), r.Status, b)}
+	}
+
+	var f DataFmt
+	if hr.Format != nil {
+		f = *hr.Format
+	} else {
+		var ok bool
+		ct := r.Header.Get("Content-Type")
+		if f, ok = ContentTypeDataFmt(ct); !ok {
+			log.Infof("Unknown Content-Type: %q, defaults to JSON", ct)
+			f = JSON
+		}
+	}
+
+	v, err := UnmarshalDataDelim(body, f, hr.Delimiter)
+	if err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// allowed returns true if the response status code is 2xx or one of the allowed status codes.
+func (hr HTTPRequest) allowed(code int) bool {
+	if code >= 200 && code < 300 {
+		return true
+	}
+	for _, c := range hr.AllowedStatus {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
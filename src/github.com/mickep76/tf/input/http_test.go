@@ -0,0 +1,110 @@
+package input
+
+import (
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func Test_GetHTTP(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		if r.Method != "POST" || r.URL.Query().Get("site") != "sto" || r.Header.Get("Authorization") != "Bearer secret" ||
+			r.Header.Get("X-Url") != "http://example.com" || string(b) != `{"host": "host1"}` {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"serialno": "abc123"}`))
+	}))
+	defer ts.Close()
+
+	opts := NewOptions(httpProvider{})
+	opts["http_url"] = ts.URL
+	opts["http_method"] = "post"
+	opts["http_headers"] = map[string]string{"X-Url": "http://example.com"}
+	opts["http_query"] = map[string]string{"site": "sto"}
+	opts["http_token_env"] = "TF_TEST_TOKEN"
+	opts["http_body"] = `{"host": "{{ .Host }}"}`
+
+	t.Setenv("TF_TEST_TOKEN", "secret")
+	v, err := httpProvider{}.Get(opts, map[string]interface{}{"Host": "host1"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if m, _ := v.(map[string]interface{}); m["serialno"] != "abc123" {
+		t.Errorf("GetHTTP didn't return expected result: %v", v)
+	} else {
+		t.Log("GetHTTP test passes")
+	}
+}
+
+func Test_GetHTTPStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/yaml":
+			w.Header().Set("Content-Type", "application/x-yaml; charset=utf-8")
+			w.Write([]byte("serialno: abc123\n"))
+		case "/gone":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusGone)
+			w.Write([]byte(`{"serialno": "none"}`))
+		default:
+			http.Error(w, "internal error", http.StatusInternalServerError)
+		}
+	}))
+	defer ts.Close()
+
+	if v, err := GetHTTP(HTTPRequest{URL: ts.URL + "/yaml"}); err != nil || v.(map[string]interface{})["serialno"] != "abc123" {
+		t.Errorf("GetHTTP didn't use format from Content-Type: %v %v", v, err)
+	} else {
+		t.Log("GetHTTP test passes")
+	}
+
+	if _, err := GetHTTP(HTTPRequest{URL: ts.URL + "/error"}); err == nil || !strings.Contains(err.Error(), "500 Internal Server Error: internal error") {
+		t.Errorf("GetHTTP didn't return expected error: %v", err)
+	} else {
+		t.Log("GetHTTP test passes")
+	}
+
+	if v, err := GetHTTP(HTTPRequest{URL: ts.URL + "/gone", AllowedStatus: []int{410}}); err != nil || v.(map[string]interface{})["serialno"] != "none" {
+		t.Errorf("GetHTTP didn't allow status code: %v %v", v, err)
+	} else {
+		t.Log("GetHTTP test passes")
+	}
+}
+
+func Test_GetHTTPTLS(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"serialno": "abc123"}`))
+	}))
+	defer ts.Close()
+
+	f, err := ioutil.TempFile("", "tf")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.Remove(f.Name())
+	pem.Encode(f, &pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+	f.Close()
+
+	if _, err := GetHTTP(HTTPRequest{URL: ts.URL}); err == nil {
+		t.Error("GetHTTP didn't fail for an untrusted certificate")
+	}
+
+	opts := NewOptions(httpProvider{})
+	opts["http_url"] = ts.URL
+	opts["http_ca"] = f.Name()
+	if v, err := (httpProvider{}).Get(opts, nil); err != nil {
+		t.Errorf("GetHTTP failed using CA file: %v", err)
+	} else if m, _ := v.(map[string]interface{}); m["serialno"] != "abc123" {
+		t.Errorf("GetHTTP didn't return expected result: %v", v)
+	} else {
+		t.Log("GetHTTP test passes")
+	}
+}
@@ -0,0 +1,57 @@
+package input
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// unquote removes matching single or double quotes around a value.
+func unquote(s string) string {
+	if len(s) > 1 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// UnmarshalINI unmarshal INI data, sections are returned as nested maps and keys before the first section are
+// placed in the root. Lines starting with ";" or "#" are comments.
+func UnmarshalINI(cont []byte) (map[string]interface{}, error) {
+	v := make(map[string]interface{})
+	sect := v
+
+	s := bufio.NewScanner(bytes.NewReader(cont))
+	for n := 1; s.Scan(); n++ {
+		l := strings.TrimSpace(s.Text())
+		if l == "" || l[0] == ';' || l[0] == '#' {
+			continue
+		}
+
+		if l[0] == '[' {
+			if l[len(l)-1] != ']' {
+				return nil, fmt.Errorf("Incorrect INI section on line %d: %s", n, l)
+			}
+
+			name := strings.TrimSpace(l[1 : len(l)-1])
+			if m, ok := v[name].(map[string]interface{}); ok {
+				sect = m
+			} else {
+				sect = make(map[string]interface{})
+				v[name] = sect
+			}
+			continue
+		}
+
+		i := strings.IndexAny(l, "=:")
+		if i < 1 {
+			return nil, fmt.Errorf("Incorrect INI key value on line %d: %s", n, l)
+		}
+		sect[strings.TrimSpace(l[:i])] = unquote(strings.TrimSpace(l[i+1:]))
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
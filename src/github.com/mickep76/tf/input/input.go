@@ -1,25 +1,22 @@
 package input
 
 import (
-	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
-	"net/http"
+	"mime"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/BurntSushi/toml"
 	log "github.com/Sirupsen/logrus"
-	_ "github.com/go-sql-driver/mysql"
-	"gopkg.in/yaml.v2"
 
 	"github.com/mickep76/tf/template"
 )
 
-// DataFmt represents which data serialization is used YAML, JSON or TOML.
+// DataFmt represents which data serialization is used, such as YAML, JSON or TOML.
 type DataFmt int
 
 // Constants for data format.
@@ -27,31 +24,165 @@ const (
 	YAML DataFmt = iota
 	TOML
 	JSON
+	INI
+	PROPERTIES
+	DOTENV
+	CSV
+	TSV
+	XML
+	HCL
+	NDJSON
 )
 
-// UnmarshalData unmarshal YAML/JSON/TOML serialized data.
-func UnmarshalData(cont []byte, f DataFmt) (map[string]interface{}, error) {
-	v := make(map[string]interface{})
+// ParseDataFmt parses the name of a data format YAML, TOML, JSON, NDJSON, INI, PROPERTIES, DOTENV, CSV, TSV, XML
+// or HCL.
+func ParseDataFmt(s string) (DataFmt, error) {
+	switch s {
+	case "YAML":
+		return YAML, nil
+	case "TOML":
+		return TOML, nil
+	case "JSON":
+		return JSON, nil
+	case "NDJSON":
+		return NDJSON, nil
+	case "INI":
+		return INI, nil
+	case "PROPERTIES":
+		return PROPERTIES, nil
+	case "DOTENV":
+		return DOTENV, nil
+	case "CSV":
+		return CSV, nil
+	case "TSV":
+		return TSV, nil
+	case "XML":
+		return XML, nil
+	case "HCL":
+		return HCL, nil
+	}
+	return 0, errors.New("Unsupported data format, needs to be YAML, JSON, NDJSON, TOML, INI, PROPERTIES, DOTENV, CSV, TSV, XML or HCL")
+}
+
+// ContentTypeDataFmt returns the data format for a HTTP Content-Type.
+func ContentTypeDataFmt(ct string) (DataFmt, bool) {
+	mt, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return 0, false
+	}
+
+	switch {
+	case mt == "application/x-ndjson" || mt == "application/ndjson" || mt == "application/jsonl":
+		return NDJSON, true
+	case mt == "application/json" || strings.HasSuffix(mt, "+json"):
+		return JSON, true
+	case mt == "application/yaml" || mt == "application/x-yaml" || mt == "text/yaml" || mt == "text/x-yaml" || strings.HasSuffix(mt, "+yaml"):
+		return YAML, true
+	case mt == "application/toml" || mt == "application/x-toml" || mt == "text/toml" || mt == "text/x-toml":
+		return TOML, true
+	case mt == "text/x-ini" || mt == "application/x-ini":
+		return INI, true
+	case mt == "text/x-java-properties" || mt == "text/x-properties":
+		return PROPERTIES, true
+	case mt == "text/csv":
+		return CSV, true
+	case mt == "text/tab-separated-values":
+		return TSV, true
+	case mt == "application/xml" || mt == "text/xml" || strings.HasSuffix(mt, "+xml"):
+		return XML, true
+	case mt == "application/hcl" || mt == "application/x-hcl" || mt == "text/x-hcl":
+		return HCL, true
+	}
+	return 0, false
+}
+
+// UnmarshalData unmarshal YAML/JSON/NDJSON/TOML/INI/PROPERTIES/DOTENV/CSV/TSV/XML/HCL serialized data. A YAML stream
+// with multiple documents and NDJSON return a list of documents. The top-level value can be a map, list or scalar,
+// maps are returned as map[string]interface{}.
+func UnmarshalData(cont []byte, f DataFmt) (interface{}, error) {
+	var v interface{}
 
 	switch f {
 	case YAML:
 		log.Info("Unmarshaling YAML data")
-		err := yaml.Unmarshal(cont, &v)
-		if err != nil {
-			return nil, err
+		docs := SplitYAML(cont)
+		if len(docs) < 2 {
+			return unmarshalYAML(cont)
+		}
+
+		// A stream with multiple documents returns a list of documents.
+		log.Infof("Unmarshaling YAML stream with %d documents", len(docs))
+		l := make([]interface{}, len(docs))
+		for i, d := range docs {
+			var err error
+			if l[i], err = unmarshalYAML(d); err != nil {
+				return nil, fmt.Errorf("YAML document %d: %v", i+1, err)
+			}
 		}
+		v = l
 	case TOML:
 		log.Info("Unmarshaling TOML data")
-		err := toml.Unmarshal(cont, &v)
+		m := make(map[string]interface{})
+		err := toml.Unmarshal(cont, &m)
 		if err != nil {
 			return nil, err
 		}
+		v = m
 	case JSON:
 		log.Info("Unmarshaling JSON data")
 		err := json.Unmarshal(cont, &v)
 		if err != nil {
 			return nil, err
 		}
+	case NDJSON:
+		log.Info("Unmarshaling NDJSON data")
+		l, err := UnmarshalNDJSON(cont)
+		if err != nil {
+			return nil, err
+		}
+		v = l
+	case INI:
+		log.Info("Unmarshaling INI data")
+		m, err := UnmarshalINI(cont)
+		if err != nil {
+			return nil, err
+		}
+		v = m
+	case PROPERTIES:
+		log.Info("Unmarshaling properties data")
+		m, err := UnmarshalProperties(cont)
+		if err != nil {
+			return nil, err
+		}
+		v = m
+	case DOTENV:
+		log.Info("Unmarshaling dotenv data")
+		m, err := UnmarshalDotenv(cont)
+		if err != nil {
+			return nil, err
+		}
+		v = m
+	case CSV, TSV:
+		log.Info("Unmarshaling CSV data")
+		l, err := UnmarshalCSV(cont, DefaultDelimiter(f))
+		if err != nil {
+			return nil, err
+		}
+		v = l
+	case XML:
+		log.Info("Unmarshaling XML data")
+		m, err := UnmarshalXML(cont)
+		if err != nil {
+			return nil, err
+		}
+		v = m
+	case HCL:
+		log.Info("Unmarshaling HCL data")
+		m, err := UnmarshalHCL(cont)
+		if err != nil {
+			return nil, err
+		}
+		v = m
 	default:
 		log.Error("Unsupported data format")
 		return nil, errors.New("Unsupported data format")
@@ -60,39 +191,61 @@ func UnmarshalData(cont []byte, f DataFmt) (map[string]interface{}, error) {
 	return v, nil
 }
 
-// LoadFile loads a file with serialized data.
-func LoadFile(fn string, data map[string]interface{}) (map[string]interface{}, error) {
-	var f DataFmt
+// FileDataFmt returns the data format for a file extension. A ".tf" extension for a templated file is ignored
+// i.e. "hosts.yaml.tf" is YAML.
+func FileDataFmt(fn string) (DataFmt, bool) {
+	if filepath.Ext(fn) == ".tf" {
+		fn = strings.TrimSuffix(fn, ".tf")
+	}
 
 	switch filepath.Ext(fn) {
-	case ".yaml":
-		f = YAML
+	case ".yaml", ".yml":
+		return YAML, true
 	case ".json":
-		f = JSON
+		return JSON, true
+	case ".ndjson", ".jsonl":
+		return NDJSON, true
 	case ".toml":
-		f = TOML
-	default:
-		log.Error("Unsupported data format, needs to be .yaml, .json or .toml")
-		return nil, errors.New("Unsupported data format")
+		return TOML, true
+	case ".ini":
+		return INI, true
+	case ".properties":
+		return PROPERTIES, true
+	case ".env":
+		return DOTENV, true
+	case ".csv":
+		return CSV, true
+	case ".tsv":
+		return TSV, true
+	case ".xml":
+		return XML, true
+	case ".hcl", ".tfvars":
+		return HCL, true
 	}
+	return 0, false
+}
 
-	_, err := os.Stat(fn)
-	if os.IsNotExist(err) {
-		log.Errorf("File doesn't exist: %s", fn)
-		return nil, err
+// fileFormat returns the data format for a file, if the data format is nil it's determined by the file extension.
+func fileFormat(fn string, format *DataFmt) (DataFmt, error) {
+	if format != nil {
+		return *format, nil
 	}
 
-	log.Infof("Reading file: %s", fn)
-	c, err := ioutil.ReadFile(fn)
-	if err != nil {
-		log.Errorf("Failed to read file: %s", fn)
-		return nil, err
+	if f, ok := FileDataFmt(fn); ok {
+		return f, nil
 	}
 
+	log.Errorf("Unsupported data format for file: %s, use a known file extension such as .yaml, .json or .toml or specify the format", fn)
+	return 0, errors.New("Unsupported data format")
+}
+
+// compileFile templates the content of a file.
+func compileFile(fn string, c []byte, data map[string]interface{}) ([]byte, error) {
 	log.Infof("Template input file: %s", fn)
 	buf, err := template.Compile(string(c), data)
 	if err != nil {
-		log.Fatal(err.Error())
+		log.Errorf("Failed to template file: %s", fn)
+		return nil, err
 	}
 
 	/*
@@ -104,114 +257,81 @@ func LoadFile(fn string, data map[string]interface{}) (map[string]interface{}, e
 	*/
 	log.Infof("Input file result: %s\n%s", fn, string(buf.Bytes()))
 
-	v, err2 := UnmarshalData(buf.Bytes(), f)
-	if err2 != nil {
-		return nil, err2
-	}
-
-	return v, nil
-}
-
-// GetOSEnv gets OS Environment variables.
-func GetOSEnv() map[string]interface{} {
-	v := make(map[string]interface{})
-
-	for _, e := range os.Environ() {
-		a := strings.Split(e, "=")
-		v[a[0]] = a[1]
-	}
-
-	return v
+	return buf.Bytes(), nil
 }
 
-// GetHTTP requests a HTTP url.
-func GetHTTP(url string, header string, f DataFmt) (map[string]interface{}, error) {
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", url, nil)
+// ReadFile reads and templates a file with serialized data. If the data format is nil it's determined by the
+// file extension.
+func ReadFile(fn string, format *DataFmt, data map[string]interface{}) ([]byte, DataFmt, error) {
+	f, err := fileFormat(fn, format)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	h := strings.Split(header, ":")
-
-	req.Header.Add(h[0], h[1])
-
-	r, err := client.Do(req)
-	if err != nil {
-		return nil, err
-	}
-
-	defer r.Body.Close()
-	body, err2 := ioutil.ReadAll(r.Body)
-	if err2 != nil {
-		return nil, err2
+	_, err = os.Stat(fn)
+	if os.IsNotExist(err) {
+		log.Errorf("File doesn't exist: %s", fn)
+		return nil, 0, err
 	}
 
-	v, err := UnmarshalData(body, f)
+	log.Infof("Reading file: %s", fn)
+	c, err := ioutil.ReadFile(fn)
 	if err != nil {
-		return nil, err
+		log.Errorf("Failed to read file: %s", fn)
+		return nil, 0, err
 	}
 
-	return v, nil
-}
-
-// GetMySQL queries MySQL.
-func GetMySQL(user string, pass string, host string, port int64, db string, qry string) ([]interface{}, error) {
-	log.Infof("Connecting to MySQL to database %s on host %s", host, db)
-	log.Infof("Connect DSN: %s:%s@tcp(%s:%v)/%s", user, "xxxxxxxx", host, port, db)
-	dbo, err := sql.Open("mysql", fmt.Sprintf("%s:%s@tcp(%s:%v)/%s", user, pass, host, port, db))
+	b, err := compileFile(fn, c, data)
 	if err != nil {
-		log.Fatal(err.Error())
+		return nil, 0, err
 	}
-	defer dbo.Close()
+	return b, f, nil
+}
 
-	err = dbo.Ping()
+// LoadBytes loads the content of a file with serialized data that has already been read, such as a file from a
+// git repository. If the data format is nil it's determined by the file extension.
+func LoadBytes(fn string, c []byte, format *DataFmt, data map[string]interface{}) (interface{}, error) {
+	f, err := fileFormat(fn, format)
 	if err != nil {
 		return nil, err
 	}
 
-	log.Infof("Execute query: %s", qry)
-	rows, err := dbo.Query(qry)
+	b, err := compileFile(fn, c, data)
 	if err != nil {
 		return nil, err
 	}
+	return UnmarshalData(b, f)
+}
 
-	log.Infof("Get result from query")
-	columns, err := rows.Columns()
+// LoadFile loads a file with serialized data.
+func LoadFile(fn string, data map[string]interface{}) (interface{}, error) {
+	b, f, err := ReadFile(fn, nil, data)
 	if err != nil {
 		return nil, err
 	}
 
-	var data []interface{}
+	return UnmarshalData(b, f)
+}
 
-	values := make([]sql.RawBytes, len(columns))
-	scanArgs := make([]interface{}, len(values))
+// GetOSEnv gets OS Environment variables.
+func GetOSEnv() map[string]interface{} {
+	v := make(map[string]interface{})
 
-	for i := range values {
-		scanArgs[i] = &values[i]
+	for _, e := range os.Environ() {
+		// Values can contain "=".
+		a := strings.SplitN(e, "=", 2)
+		v[a[0]] = a[1]
 	}
 
-	for rows.Next() {
-		err = rows.Scan(scanArgs...)
-		if err != nil {
-			return nil, err
-		}
+	return v
+}
 
-		var value string
-		res := make(map[string]interface{})
-		for i, col := range values {
-			if col == nil {
-				value = "NULL"
-			} else {
-				value = string(col)
-			}
-			res[columns[i]] = value
-		}
-		data = append(data, res)
-	}
-	if err = rows.Err(); err != nil {
-		return nil, err
+// readSecret reads a password or token from a file, trailing newlines are removed.
+func readSecret(fn string) (string, error) {
+	log.Infof("Reading secret from file: %s", fn)
+	c, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return "", err
 	}
-
-	return data, nil
+	return strings.TrimRight(string(c), "\r\n"), nil
 }
@@ -0,0 +1,171 @@
+package input
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_UnmarshalData(t *testing.T) {
+	v, err := UnmarshalData([]byte("site: sto\nhosts: 2\n"), YAML)
+	if m, ok := v.(map[string]interface{}); err != nil || !ok || m["site"] != "sto" {
+		t.Errorf("UnmarshalData didn't return expected map: %v", v)
+	} else {
+		t.Log("UnmarshalData test passes")
+	}
+
+	v, err = UnmarshalData([]byte(`[{"name": "host1"}, {"name": "host2"}]`), JSON)
+	if l, ok := v.([]interface{}); err != nil || !ok || len(l) != 2 {
+		t.Errorf("UnmarshalData didn't return expected list: %v", v)
+	} else {
+		t.Log("UnmarshalData test passes")
+	}
+
+	v, err = UnmarshalData([]byte("- host1\n- host2\n"), YAML)
+	if l, ok := v.([]interface{}); err != nil || !ok || l[1] != "host2" {
+		t.Errorf("UnmarshalData didn't return expected list: %v", v)
+	} else {
+		t.Log("UnmarshalData test passes")
+	}
+
+	v, err = UnmarshalData([]byte("42"), JSON)
+	if err != nil || v != float64(42) {
+		t.Errorf("UnmarshalData didn't return expected scalar: %v", v)
+	} else {
+		t.Log("UnmarshalData test passes")
+	}
+}
+
+func Test_UnmarshalINI(t *testing.T) {
+	v, err := UnmarshalINI([]byte("; comment\nname = app\n\n[database]\nhost = db1\nport: 5432\nuser = \"admin\"\n"))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if db, ok := v["database"].(map[string]interface{}); !ok || v["name"] != "app" || db["host"] != "db1" ||
+		db["port"] != "5432" || db["user"] != "admin" {
+		t.Errorf("UnmarshalINI didn't return expected result: %v", v)
+	} else {
+		t.Log("UnmarshalINI test passes")
+	}
+
+	if _, err := UnmarshalINI([]byte("[database\n")); err == nil {
+		t.Error("UnmarshalINI didn't return expected error")
+	} else {
+		t.Log("UnmarshalINI test passes")
+	}
+}
+
+func Test_UnmarshalProperties(t *testing.T) {
+	v, err := UnmarshalProperties([]byte("# comment\n! comment\ndb.host=db1\ndb.port : 5432\nname app\n" +
+		"list = a, \\\n    b\npath=c:\\\\tmp\nkey\\ with\\ space=\\u0041\n"))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	exp := map[string]string{"db.host": "db1", "db.port": "5432", "name": "app", "list": "a, b", "path": `c:\tmp`,
+		"key with space": "A"}
+	for k, e := range exp {
+		if v[k] != e {
+			t.Errorf("UnmarshalProperties didn't return expected value for %q: %q", k, v[k])
+		}
+	}
+	t.Log("UnmarshalProperties test passes")
+
+	v, err = UnmarshalProperties([]byte("a=1\nb=2\\"))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(v) != 2 || v["a"] != "1" || v["b"] != "2" {
+		t.Errorf("UnmarshalProperties didn't return the last line ending with a continuation: %v", v)
+	} else {
+		t.Log("UnmarshalProperties test passes")
+	}
+}
+
+func Test_UnmarshalDotenv(t *testing.T) {
+	v, err := UnmarshalDotenv([]byte("# comment\nexport HOST=db1\nPORT=5432 # port\nMSG=\"a\\nb\"\nRAW='a\\nb'\nEMPTY=\n"))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	exp := map[string]string{"HOST": "db1", "PORT": "5432", "MSG": "a\nb", "RAW": `a\nb`, "EMPTY": ""}
+	for k, e := range exp {
+		if v[k] != e {
+			t.Errorf("UnmarshalDotenv didn't return expected value for %q: %q", k, v[k])
+		}
+	}
+	t.Log("UnmarshalDotenv test passes")
+
+	if _, err := UnmarshalDotenv([]byte("MSG=\"abc\n")); err == nil {
+		t.Error("UnmarshalDotenv didn't return expected error")
+	} else {
+		t.Log("UnmarshalDotenv test passes")
+	}
+}
+
+func Test_UnmarshalYAMLStream(t *testing.T) {
+	v, err := UnmarshalData([]byte("---\n# first\nkind: Service\n---\nkind: Deployment\nspec:\n  text: |\n    a\n    b\n...\n---\n"), YAML)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if l, ok := v.([]interface{}); !ok || len(l) != 2 || l[1].(map[string]interface{})["kind"] != "Deployment" {
+		t.Errorf("UnmarshalData didn't return expected list of documents: %v", v)
+	} else {
+		t.Log("UnmarshalData test passes")
+	}
+
+	if v, err := UnmarshalData([]byte("---\nkind: Service\n"), YAML); err != nil || v.(map[string]interface{})["kind"] != "Service" {
+		t.Errorf("UnmarshalData didn't return a single document: %v %v", v, err)
+	} else {
+		t.Log("UnmarshalData test passes")
+	}
+}
+
+func Test_UnmarshalNDJSON(t *testing.T) {
+	v, err := UnmarshalData([]byte("{\"host\": \"web1\"}\n\n{\"host\": \"web2\"}\n"), NDJSON)
+	if l, ok := v.([]interface{}); err != nil || !ok || len(l) != 2 || l[1].(map[string]interface{})["host"] != "web2" {
+		t.Errorf("UnmarshalData didn't return expected list of documents: %v %v", v, err)
+	} else {
+		t.Log("UnmarshalData test passes")
+	}
+
+	if _, err := UnmarshalNDJSON([]byte("{}\n{\n")); err == nil || !strings.HasPrefix(err.Error(), "NDJSON line 2") {
+		t.Errorf("UnmarshalNDJSON didn't return expected error: %v", err)
+	} else {
+		t.Log("UnmarshalNDJSON test passes")
+	}
+}
+
+func Test_FileDataFmt(t *testing.T) {
+	for fn, e := range map[string]DataFmt{"hosts.yml": YAML, "hosts.yaml.tf": YAML, "/etc/app/hosts.json": JSON, "vars.tfvars": HCL} {
+		if f, ok := FileDataFmt(fn); !ok || f != e {
+			t.Errorf("FileDataFmt didn't return expected format for: %s", fn)
+		} else {
+			t.Log("FileDataFmt test passes")
+		}
+	}
+
+	for _, fn := range []string{"/etc/app/config", "main.tf"} {
+		if _, ok := FileDataFmt(fn); ok {
+			t.Errorf("FileDataFmt didn't return expected error for: %s", fn)
+		} else {
+			t.Log("FileDataFmt test passes")
+		}
+	}
+}
+
+func Test_LoadBytes(t *testing.T) {
+	v, err := LoadBytes("app.yaml", []byte("host: {{ .Host }}\n"), nil, map[string]interface{}{"Host": "web1"})
+	if m, ok := v.(map[string]interface{}); err != nil || !ok || m["host"] != "web1" {
+		t.Errorf("LoadBytes didn't return expected result: %v %v", v, err)
+	} else {
+		t.Log("LoadBytes test passes")
+	}
+
+	if _, err := LoadBytes("app.yaml", []byte("host: {{ .Host\n"), nil, nil); err == nil {
+		t.Error("LoadBytes didn't return expected error for an incorrect template")
+	} else {
+		t.Log("LoadBytes test passes")
+	}
+}
@@ -0,0 +1,189 @@
+package input
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	"gopkg.in/ldap.v2"
+)
+
+type ldapProvider struct{}
+
+func init() {
+	Register("ldap", ldapProvider{})
+}
+
+func (ldapProvider) Namespace() string {
+	return "LDAP"
+}
+
+func (ldapProvider) Remote() bool {
+	return true
+}
+
+func (ldapProvider) Options() []Option {
+	return []Option{
+		{Key: "ldap_url", Description: "LDAP url ldap://host:port or ldaps://host:port", Required: true, Shared: true},
+		{Key: "ldap_starttls", Description: "LDAP use StartTLS", Type: OptBool, Shared: true},
+		{Key: "ldap_ca", Description: "LDAP CA file for verifying the server certificate", Shared: true},
+		{Key: "ldap_insecure", Description: "LDAP skip verifying the server certificate", Type: OptBool, Shared: true},
+		{Key: "ldap_bind_dn", Description: "LDAP bind DN", Shared: true},
+		{Key: "ldap_password", Description: "LDAP bind password", Shared: true},
+		{Key: "ldap_password_file", Description: "LDAP bind password file", Shared: true},
+		{Key: "ldap_base_dn", Description: "LDAP base DN", Required: true, Shared: true},
+		{Key: "ldap_filter", Description: "LDAP filter", Default: "(objectClass=*)"},
+		{Key: "ldap_scope", Description: "LDAP scope base, one or sub", Default: "sub"},
+		{Key: "ldap_attributes", Description: "LDAP attributes, defaults to all", Type: OptList},
+		{Key: "ldap_multi_valued", Description: "LDAP attributes always returned as lists, * for all attributes", Type: OptList},
+	}
+}
+
+func (ldapProvider) Get(opts Options, data map[string]interface{}) (interface{}, error) {
+	pass := opts.String("ldap_password")
+	if opts.IsSet("ldap_password_file") {
+		var err error
+		if pass, err = readSecret(opts.String("ldap_password_file")); err != nil {
+			return nil, err
+		}
+	}
+
+	tlsCfg, err := TLSConfig(opts.String("ldap_ca"), "", "", opts.Bool("ldap_insecure"))
+	if err != nil {
+		return nil, err
+	}
+
+	return GetLDAP(opts.String("ldap_url"), tlsCfg, opts.Bool("ldap_starttls"), opts.String("ldap_bind_dn"), pass,
+		opts.String("ldap_base_dn"), opts.String("ldap_filter"), opts.String("ldap_scope"), opts.List("ldap_attributes"),
+		opts.List("ldap_multi_valued"), opts.Duration("timeout"))
+}
+
+// ldapPageSize is the number of entries requested per page, so results aren't limited by the server size limit.
+const ldapPageSize = 500
+
+// ldapError marks errors for a server that is busy, unavailable or a lost connection as temporary.
+func ldapError(err error) error {
+	for _, c := range []uint8{ldap.LDAPResultBusy, ldap.LDAPResultUnavailable, ldap.ErrorNetwork} {
+		if ldap.IsErrorWithCode(err, c) {
+			return temporaryError{err: err}
+		}
+	}
+	return err
+}
+
+// ldapMultiValued returns true if an attribute is in the list of attributes always returned as lists.
+func ldapMultiValued(name string, multi []string) bool {
+	for _, m := range multi {
+		if m == "*" || strings.EqualFold(m, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetLDAP searches a LDAP directory using paging. Entries are returned as a list of maps keyed by attribute, with
+// the distinguished name as "dn". Attributes with several values or listed in multi are returned as lists, use "*"
+// to return all attributes as lists.
+func GetLDAP(u string, tlsCfg *tls.Config, startTLS bool, bindDN string, pass string, baseDN string, filter string,
+	scope string, attrs []string, multi []string, timeout time.Duration) ([]interface{}, error) {
+	var s int
+	switch scope {
+	case "base":
+		s = ldap.ScopeBaseObject
+	case "one":
+		s = ldap.ScopeSingleLevel
+	case "sub":
+		s = ldap.ScopeWholeSubtree
+	default:
+		return nil, errors.New("Unsupported LDAP scope, needs to be base, one or sub")
+	}
+
+	pu, err := url.Parse(u)
+	if err != nil {
+		return nil, err
+	}
+
+	host := pu.Host
+	if pu.Port() == "" {
+		port := "389"
+		if pu.Scheme == "ldaps" {
+			port = "636"
+		}
+		host = net.JoinHostPort(pu.Hostname(), port)
+	}
+
+	if tlsCfg.ServerName == "" {
+		tlsCfg.ServerName = pu.Hostname()
+	}
+
+	log.Infof("Connecting to LDAP: %s", u)
+	var c net.Conn
+	d := &net.Dialer{Timeout: timeout}
+	switch pu.Scheme {
+	case "ldap":
+		c, err = d.Dial("tcp", host)
+	case "ldaps":
+		c, err = tls.DialWithDialer(d, "tcp", host, tlsCfg)
+	default:
+		return nil, fmt.Errorf("Unsupported LDAP url scheme: %s, needs to be ldap or ldaps", pu.Scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	l := ldap.NewConn(c, pu.Scheme == "ldaps")
+	l.Start()
+	defer l.Close()
+	if timeout > 0 {
+		l.SetTimeout(timeout)
+	}
+
+	if startTLS {
+		if pu.Scheme == "ldaps" {
+			return nil, errors.New("Can't use StartTLS with a ldaps url")
+		}
+
+		log.Infof("Starting TLS")
+		if err := l.StartTLS(tlsCfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if bindDN != "" {
+		log.Infof("Binding as: %s", bindDN)
+		if err := l.Bind(bindDN, pass); err != nil {
+			return nil, ldapError(err)
+		}
+	}
+
+	log.Infof("Search base DN: %s filter: %s scope: %s", baseDN, filter, scope)
+	req := ldap.NewSearchRequest(baseDN, s, ldap.NeverDerefAliases, 0, 0, false, filter, attrs, nil)
+	res, err := l.SearchWithPaging(req, ldapPageSize)
+	if err != nil {
+		return nil, ldapError(err)
+	}
+
+	data := make([]interface{}, 0, len(res.Entries))
+	for _, e := range res.Entries {
+		m := map[string]interface{}{"dn": e.DN}
+		for _, a := range e.Attributes {
+			if len(a.Values) == 1 && !ldapMultiValued(a.Name, multi) {
+				m[a.Name] = a.Values[0]
+			} else {
+				l := make([]interface{}, len(a.Values))
+				for i, v := range a.Values {
+					l[i] = v
+				}
+				m[a.Name] = l
+			}
+		}
+		data = append(data, m)
+	}
+
+	return data, nil
+}
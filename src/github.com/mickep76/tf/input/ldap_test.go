@@ -0,0 +1,126 @@
+package input
+
+import (
+	"crypto/tls"
+	"net"
+	"testing"
+	"time"
+
+	"gopkg.in/asn1-ber.v1"
+)
+
+// fakeLDAPResult creates a LDAP result with result code success.
+func fakeLDAPResult(id int64, tag ber.Tag) *ber.Packet {
+	p := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	p.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "MessageID"))
+	r := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	r.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, 0, "Result Code"))
+	r.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	r.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic"))
+	p.AppendChild(r)
+	return p
+}
+
+// fakeLDAPEntry creates a LDAP search result entry.
+func fakeLDAPEntry(id int64, dn string, attrs map[string][]string) *ber.Packet {
+	p := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	p.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "MessageID"))
+	e := ber.Encode(ber.ClassApplication, ber.TypeConstructed, 4, nil, "Search Result Entry")
+	e.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, dn, "DN"))
+	l := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+	for k, vals := range attrs {
+		a := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+		a.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, k, "Type"))
+		s := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+		for _, v := range vals {
+			s.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, v, "Value"))
+		}
+		a.AppendChild(s)
+		l.AppendChild(a)
+	}
+	e.AppendChild(l)
+	p.AppendChild(e)
+	return p
+}
+
+// fakeLDAPServer answers bind and paged search requests with a fixed result.
+func fakeLDAPServer(ln net.Listener) {
+	for {
+		c, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		go fakeLDAPConn(c)
+	}
+}
+
+// fakeLDAPConn answers requests on a connection, the paging control is ignored so the result is a single page.
+func fakeLDAPConn(c net.Conn) {
+	defer c.Close()
+
+	for {
+		req, err := ber.ReadPacket(c)
+		if err != nil {
+			return
+		}
+
+		id := req.Children[0].Value.(int64)
+		switch req.Children[1].Tag {
+		case 0:
+			c.Write(fakeLDAPResult(id, 1).Bytes())
+		case 3:
+			if len(req.Children) < 3 {
+				// No paging control.
+				return
+			}
+			c.Write(fakeLDAPEntry(id, "cn=admins,ou=groups,dc=example,dc=com", map[string][]string{
+				"cn":        {"admins"},
+				"memberUid": {"alice", "bob"},
+			}).Bytes())
+			c.Write(fakeLDAPResult(id, 5).Bytes())
+		default:
+			return
+		}
+	}
+}
+
+func Test_GetLDAP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer ln.Close()
+	go fakeLDAPServer(ln)
+
+	rows, err := GetLDAP("ldap://"+ln.Addr().String(), &tls.Config{}, false, "cn=admin,dc=example,dc=com", "secret",
+		"ou=groups,dc=example,dc=com", "(objectClass=posixGroup)", "sub", nil, nil, time.Second)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(rows) != 1 {
+		t.Error("GetLDAP didn't return expected number of entries")
+	} else if e := rows[0].(map[string]interface{}); e["dn"] != "cn=admins,ou=groups,dc=example,dc=com" || e["cn"] != "admins" {
+		t.Errorf("GetLDAP didn't return expected result: %v", e)
+	} else if m, ok := e["memberUid"].([]interface{}); !ok || len(m) != 2 || m[1] != "bob" {
+		t.Errorf("GetLDAP didn't return expected multi-valued attribute: %v", e)
+	} else {
+		t.Log("GetLDAP test passes")
+	}
+
+	rows, err = GetLDAP("ldap://"+ln.Addr().String(), &tls.Config{}, false, "", "", "ou=groups,dc=example,dc=com",
+		"(objectClass=posixGroup)", "sub", nil, []string{"*"}, time.Second)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(rows) != 1 {
+		t.Error("GetLDAP didn't return expected number of entries")
+	} else if e := rows[0].(map[string]interface{}); e["dn"] != "cn=admins,ou=groups,dc=example,dc=com" {
+		t.Errorf("GetLDAP didn't return dn as a string: %v", e)
+	} else if cn, ok := e["cn"].([]interface{}); !ok || len(cn) != 1 || cn[0] != "admins" {
+		t.Errorf("GetLDAP didn't return single value as a list for multi-valued attributes: %v", e)
+	} else {
+		t.Log("GetLDAP multi-valued test passes")
+	}
+}
@@ -0,0 +1,48 @@
+package input
+
+import (
+	"fmt"
+)
+
+// StringMap returns a map with string keys, YAML maps with interface{} keys are converted.
+func StringMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m, true
+	case map[interface{}]interface{}:
+		r := make(map[string]interface{}, len(m))
+		for k, e := range m {
+			r[fmt.Sprintf("%v", k)] = e
+		}
+		return r, true
+	}
+	return nil, false
+}
+
+// DeepMerge merges src into dst and returns the result. Maps are merged recursively, lists are replaced or
+// appended to if appendLists is true and any other value is replaced. The dst maps are modified.
+func DeepMerge(dst interface{}, src interface{}, appendLists bool) interface{} {
+	if s, ok := StringMap(src); ok {
+		d, ok := StringMap(dst)
+		if !ok {
+			return src
+		}
+
+		for k, v := range s {
+			if dv, ok := d[k]; ok {
+				d[k] = DeepMerge(dv, v, appendLists)
+			} else {
+				d[k] = v
+			}
+		}
+		return d
+	}
+
+	if s, ok := src.([]interface{}); ok && appendLists {
+		if d, ok := dst.([]interface{}); ok {
+			l := make([]interface{}, 0, len(d)+len(s))
+			return append(append(l, d...), s...)
+		}
+	}
+	return src
+}
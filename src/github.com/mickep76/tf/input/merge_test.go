@@ -0,0 +1,32 @@
+package input
+
+import (
+	"testing"
+)
+
+func Test_DeepMerge(t *testing.T) {
+	a, _ := UnmarshalData([]byte("db:\n  host: db1\n  port: 5432\nhosts: [web1]\n"), YAML)
+	b, _ := UnmarshalData([]byte(`{"db": {"host": "db2"}, "hosts": ["web2"], "site": "sto"}`), JSON)
+
+	v := DeepMerge(a, b, false).(map[string]interface{})
+	db := v["db"].(map[string]interface{})
+	if db["host"] != "db2" || db["port"] != 5432 || v["site"] != "sto" || len(v["hosts"].([]interface{})) != 1 {
+		t.Errorf("DeepMerge didn't return expected result: %v", v)
+	} else {
+		t.Log("DeepMerge test passes")
+	}
+
+	c, _ := UnmarshalData([]byte("hosts: [web3]\n"), YAML)
+	v = DeepMerge(v, c, true).(map[string]interface{})
+	if l := v["hosts"].([]interface{}); len(l) != 2 || l[0] != "web2" || l[1] != "web3" {
+		t.Errorf("DeepMerge didn't append lists: %v", v["hosts"])
+	} else {
+		t.Log("DeepMerge test passes")
+	}
+
+	if r := DeepMerge(v, []interface{}{"a"}, true); len(r.([]interface{})) != 1 {
+		t.Errorf("DeepMerge didn't replace a map with a list: %v", r)
+	} else {
+		t.Log("DeepMerge test passes")
+	}
+}
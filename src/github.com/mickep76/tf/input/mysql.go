@@ -0,0 +1,51 @@
+package input
+
+import (
+	"fmt"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	_ "github.com/go-sql-driver/mysql"
+)
+
+type mysqlProvider struct{}
+
+func init() {
+	Register("mysql", mysqlProvider{})
+}
+
+func (mysqlProvider) Namespace() string {
+	return "MySQL"
+}
+
+func (mysqlProvider) Remote() bool {
+	return true
+}
+
+func (mysqlProvider) Options() []Option {
+	return []Option{
+		{Key: "mysql_user", Description: "MySQL user", Required: true, Shared: true},
+		{Key: "mysql_password", Description: "MySQL password", Required: true, Shared: true},
+		{Key: "mysql_host", Description: "MySQL host", Required: true, Shared: true},
+		{Key: "mysql_port", Description: "MySQL port", Type: OptInt, Default: int64(3306), Shared: true},
+		{Key: "mysql_database", Description: "MySQL database", Required: true, Shared: true},
+		{Key: "mysql_query", Description: "MySQL query", Required: true},
+		{Key: "mysql_select", Description: "MySQL select from the rows such as [0] or [].name"},
+	}
+}
+
+func (mysqlProvider) Get(opts Options, data map[string]interface{}) (interface{}, error) {
+	v, err := GetMySQL(opts.String("mysql_user"), opts.String("mysql_password"), opts.String("mysql_host"),
+		opts.Int("mysql_port"), opts.String("mysql_database"), opts.String("mysql_query"), opts.Duration("timeout"))
+	if err != nil {
+		return nil, err
+	}
+	return Select(v, opts.String("mysql_select"))
+}
+
+// GetMySQL queries MySQL.
+func GetMySQL(user string, pass string, host string, port int64, db string, qry string, timeout time.Duration) ([]interface{}, error) {
+	log.Infof("Connecting to MySQL to database %s on host %s", db, host)
+	log.Infof("Connect DSN: %s:%s@tcp(%s:%v)/%s", user, "xxxxxxxx", host, port, db)
+	return GetSQL("mysql", fmt.Sprintf("%s:%s@tcp(%s:%v)/%s", user, pass, host, port, db), qry, false, timeout)
+}
@@ -0,0 +1,61 @@
+package input
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+	_ "github.com/lib/pq"
+)
+
+type postgresProvider struct{}
+
+func init() {
+	Register("postgres", postgresProvider{})
+}
+
+func (postgresProvider) Namespace() string {
+	return "Postgres"
+}
+
+func (postgresProvider) Remote() bool {
+	return true
+}
+
+func (postgresProvider) Options() []Option {
+	return []Option{
+		{Key: "postgres_user", Description: "PostgreSQL user", Required: true, Shared: true},
+		{Key: "postgres_password", Description: "PostgreSQL password", Shared: true},
+		{Key: "postgres_host", Description: "PostgreSQL host", Required: true, Shared: true},
+		{Key: "postgres_port", Description: "PostgreSQL port", Type: OptInt, Default: int64(5432), Shared: true},
+		{Key: "postgres_database", Description: "PostgreSQL database", Required: true, Shared: true},
+		{Key: "postgres_sslmode", Description: "PostgreSQL SSL mode disable, require, verify-ca or verify-full", Default: "require", Shared: true},
+		{Key: "postgres_query", Description: "PostgreSQL query", Required: true},
+	}
+}
+
+func (postgresProvider) Get(opts Options, data map[string]interface{}) (interface{}, error) {
+	return GetPostgres(opts.String("postgres_user"), opts.String("postgres_password"), opts.String("postgres_host"),
+		opts.Int("postgres_port"), opts.String("postgres_database"), opts.String("postgres_sslmode"), opts.String("postgres_query"),
+		opts.Duration("timeout"))
+}
+
+// GetPostgres queries PostgreSQL.
+func GetPostgres(user string, pass string, host string, port int64, db string, sslmode string, qry string,
+	timeout time.Duration) ([]interface{}, error) {
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.User(user),
+		Host:     fmt.Sprintf("%s:%v", host, port),
+		Path:     "/" + db,
+		RawQuery: url.Values{"sslmode": []string{sslmode}}.Encode(),
+	}
+
+	log.Infof("Connecting to PostgreSQL to database %s on host %s", db, host)
+	log.Infof("Connect DSN: %s", u.String())
+	if pass != "" {
+		u.User = url.UserPassword(user, pass)
+	}
+	return GetSQL("postgres", u.String(), qry, false, timeout)
+}
@@ -0,0 +1,126 @@
+package input
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// unescapeProperty replaces the escape sequences used in Java properties.
+func unescapeProperty(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+4 >= len(s) {
+				return "", fmt.Errorf("Incorrect unicode escape in: %s", s)
+			}
+			r, err := strconv.ParseUint(s[i+1:i+5], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf("Incorrect unicode escape in: %s", s)
+			}
+			b.WriteRune(rune(r))
+			i += 4
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), nil
+}
+
+// propertyLine splits a logical line into an unescaped key and value.
+func propertyLine(l string) (string, string, error) {
+	// Find the first unescaped separator.
+	i := 0
+	for ; i < len(l); i++ {
+		if l[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.IndexByte("=: \t\f", l[i]) >= 0 {
+			break
+		}
+	}
+	if i > len(l) {
+		i = len(l)
+	}
+
+	// Whitespace around the separator is ignored.
+	key, val := l[:i], strings.TrimLeft(l[i:], " \t\f")
+	if len(val) > 0 && (val[0] == '=' || val[0] == ':') {
+		val = strings.TrimLeft(val[1:], " \t\f")
+	}
+
+	k, err := unescapeProperty(key)
+	if err != nil {
+		return "", "", err
+	}
+	v, err := unescapeProperty(val)
+	if err != nil {
+		return "", "", err
+	}
+	return k, v, nil
+}
+
+// UnmarshalProperties unmarshal Java properties data. Keys and values are separated by "=", ":" or whitespace
+// and lines ending with "\" continue on the next line. Keys are kept as is, i.e. "a.b" isn't nested.
+func UnmarshalProperties(cont []byte) (map[string]interface{}, error) {
+	v := make(map[string]interface{})
+
+	s := bufio.NewScanner(bytes.NewReader(cont))
+	var l string
+	for s.Scan() {
+		t := strings.TrimLeft(s.Text(), " \t\f")
+		if l == "" && (t == "" || t[0] == '#' || t[0] == '!') {
+			continue
+		}
+
+		// An odd number of trailing backslashes continues the line.
+		bs := len(t) - len(strings.TrimRight(t, `\`))
+		if bs%2 == 1 {
+			l += t[:len(t)-1]
+			continue
+		}
+
+		k, val, err := propertyLine(l + t)
+		if err != nil {
+			return nil, err
+		}
+		v[k] = val
+		l = ""
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	// The last line ends with a continuation.
+	if l != "" {
+		k, val, err := propertyLine(l)
+		if err != nil {
+			return nil, err
+		}
+		v[k] = val
+	}
+	return v, nil
+}
@@ -0,0 +1,419 @@
+package input
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// OptType represents the value type of a provider option.
+type OptType int
+
+// Constants for option types.
+const (
+	OptString OptType = iota
+	OptInt
+	OptBool
+	// OptList is a list of strings, a string is split on commas. On the command line it can be repeated and each
+	// value is split on commas.
+	OptList
+	// OptMap is a map of strings, on the command line it's given as "key:value" and can be repeated.
+	OptMap
+	// OptDuration is a duration such as "10s", integers are seconds.
+	OptDuration
+	// OptArgs is a list of arguments such as command arguments, values are never split. On the command line it's
+	// repeated for each argument.
+	OptArgs
+)
+
+// Option describes an option supported by a provider. The key is used in the configuration file and the command
+// line flag is the key with "_" replaced by "-".
+type Option struct {
+	Key         string
+	Description string
+	Type        OptType
+	Default     interface{}
+	Required    bool
+	// Shared options can also be set in [defaults].
+	Shared bool
+}
+
+// Options contains option values for an input.
+type Options map[string]interface{}
+
+// Provider is a source of input data.
+type Provider interface {
+	// Namespace used for input given as command line flags, empty if the provider doesn't have any flags.
+	Namespace() string
+	// Options supported by the provider.
+	Options() []Option
+	// Get input data.
+	Get(opts Options, data map[string]interface{}) (interface{}, error)
+}
+
+// RemoteProvider is implemented by providers that get input over the network, these support the remote options.
+type RemoteProvider interface {
+	Provider
+	// Remote returns true if the provider gets input over the network.
+	Remote() bool
+}
+
+// IdempotentProvider is implemented by remote providers where a request isn't always safe to retry, such as a HTTP
+// POST request.
+type IdempotentProvider interface {
+	// Idempotent returns true if the request for the options is safe to retry.
+	Idempotent(opts Options) bool
+}
+
+// RemoteOptions are the options for timeout and retries supported by all remote providers.
+var RemoteOptions = []Option{
+	{Key: "timeout", Description: "Timeout for remote inputs", Type: OptDuration, Default: 30 * time.Second, Shared: true},
+	{Key: "retries", Description: "Number of retries for remote inputs", Type: OptInt, Default: int64(0), Shared: true},
+	{Key: "retry_backoff", Description: "Backoff before the first retry, doubled for each retry", Type: OptDuration, Default: time.Second, Shared: true},
+}
+
+var providers = make(map[string]Provider)
+
+// Register registers a provider for an input type.
+func Register(typ string, p Provider) {
+	if _, ok := providers[typ]; ok {
+		panic(fmt.Sprintf("Provider already registered for input type: %s", typ))
+	}
+	providers[typ] = p
+}
+
+// GetProvider returns the provider for an input type.
+func GetProvider(typ string) (Provider, bool) {
+	p, ok := providers[typ]
+	return p, ok
+}
+
+// Types returns the registered input types in sorted order.
+func Types() []string {
+	t := make([]string, 0, len(providers))
+	for k := range providers {
+		t = append(t, k)
+	}
+	sort.Strings(t)
+	return t
+}
+
+// IsRemote returns true if the provider gets input over the network.
+func IsRemote(p Provider) bool {
+	r, ok := p.(RemoteProvider)
+	return ok && r.Remote()
+}
+
+// AllOptions returns the options supported by a provider, including the remote options for remote providers.
+func AllOptions(p Provider) []Option {
+	if IsRemote(p) {
+		return append(p.Options(), RemoteOptions...)
+	}
+	return p.Options()
+}
+
+// FindOption returns the option for a key supported by a provider.
+func FindOption(p Provider, key string) (Option, bool) {
+	for _, o := range AllOptions(p) {
+		if o.Key == key {
+			return o, true
+		}
+	}
+	return Option{}, false
+}
+
+// SharedOption returns a shared option for a key supported by any provider.
+func SharedOption(key string) (Option, bool) {
+	for _, t := range Types() {
+		if o, ok := FindOption(providers[t], key); ok && o.Shared {
+			return o, true
+		}
+	}
+	return Option{}, false
+}
+
+// NewOptions returns options for a provider with the option defaults set.
+func NewOptions(p Provider) Options {
+	opts := make(Options)
+	for _, o := range AllOptions(p) {
+		if o.Default != nil {
+			opts[o.Key] = o.Default
+		}
+	}
+	return opts
+}
+
+// Missing returns the first required option that isn't set, or an empty string.
+func Missing(p Provider, opts Options) string {
+	for _, o := range AllOptions(p) {
+		if _, ok := opts[o.Key]; o.Required && !ok {
+			return o.Key
+		}
+	}
+	return ""
+}
+
+// statusError is returned when a server responds with an error status code.
+type statusError struct {
+	code int
+	msg  string
+}
+
+func (e *statusError) Error() string {
+	return e.msg
+}
+
+// Temporary returns true for server errors and too many requests.
+func (e *statusError) Temporary() bool {
+	return e.code >= 500 || e.code == 429
+}
+
+// temporaryError marks an error from a provider as temporary, so the input is retried.
+type temporaryError struct {
+	err error
+}
+
+func (e temporaryError) Error() string {
+	return e.err.Error()
+}
+
+func (e temporaryError) Temporary() bool {
+	return true
+}
+
+func (e temporaryError) Unwrap() error {
+	return e.err
+}
+
+// retryable returns true for errors worth retrying such as network errors, timeouts and errors marked as
+// temporary, such as 5xx and 429 responses.
+func retryable(err error) bool {
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	var netErr net.Error
+	var tmpErr interface {
+		Temporary() bool
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF),
+		errors.Is(err, driver.ErrBadConn):
+		return true
+	case errors.As(err, &dnsErr):
+		// Don't retry hosts that doesn't exist.
+		return dnsErr.IsTimeout || dnsErr.IsTemporary
+	case errors.As(err, &opErr):
+		return true
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return true
+	case errors.As(err, &tmpErr):
+		return tmpErr.Temporary()
+	}
+	return false
+}
+
+// Get gets input data from a provider, remote providers are retried with backoff according to the remote options.
+// Only retryable errors are retried and requests that aren't idempotent are never retried.
+func Get(name string, p Provider, opts Options, data map[string]interface{}) (interface{}, error) {
+	if !IsRemote(p) {
+		return p.Get(opts, data)
+	}
+
+	attempts := opts.Int("retries") + 1
+	if i, ok := p.(IdempotentProvider); ok && attempts > 1 && !i.Idempotent(opts) {
+		log.Warnf("Input %s isn't retried since the request isn't idempotent", name)
+		attempts = 1
+	}
+
+	backoff := opts.Duration("retry_backoff")
+	for attempt := int64(1); ; attempt++ {
+		log.Infof("Get input %s attempt %d/%d", name, attempt, attempts)
+		v, err := p.Get(opts, data)
+		if err == nil {
+			return v, nil
+		}
+
+		if attempt >= attempts || !retryable(err) {
+			if attempt > 1 {
+				return nil, fmt.Errorf("Input %s failed after %d attempts: %v", name, attempt, err)
+			}
+			return nil, err
+		}
+
+		log.Warnf("Input %s attempt %d/%d failed, retrying in %v: %v", name, attempt, attempts, backoff, err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// splitList splits a comma separated list, items are trimmed and empty items are removed.
+func splitList(s string) []string {
+	var l []string
+	for _, e := range strings.Split(s, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			l = append(l, e)
+		}
+	}
+	return l
+}
+
+// Convert converts a value to the option type.
+func (o Option) Convert(v interface{}) (interface{}, error) {
+	switch o.Type {
+	case OptString:
+		switch t := v.(type) {
+		case string:
+			return t, nil
+		case int, int64, float64, bool:
+			return fmt.Sprintf("%v", t), nil
+		}
+	case OptInt:
+		switch t := v.(type) {
+		case int:
+			return int64(t), nil
+		case int64:
+			return t, nil
+		case float64:
+			if t == float64(int64(t)) {
+				return int64(t), nil
+			}
+		case string:
+			n, err := strconv.ParseInt(t, 10, 64)
+			if err == nil {
+				return n, nil
+			}
+		}
+	case OptBool:
+		switch t := v.(type) {
+		case bool:
+			return t, nil
+		case string:
+			b, err := strconv.ParseBool(t)
+			if err == nil {
+				return b, nil
+			}
+		}
+	case OptList:
+		switch t := v.(type) {
+		case []string:
+			var l []string
+			for _, s := range t {
+				l = append(l, splitList(s)...)
+			}
+			return l, nil
+		case []interface{}:
+			l := make([]string, len(t))
+			for i, e := range t {
+				l[i] = fmt.Sprintf("%v", e)
+			}
+			return l, nil
+		case string:
+			return splitList(t), nil
+		}
+	case OptArgs:
+		switch t := v.(type) {
+		case []string:
+			return append([]string{}, t...), nil
+		case []interface{}:
+			l := make([]string, len(t))
+			for i, e := range t {
+				l[i] = fmt.Sprintf("%v", e)
+			}
+			return l, nil
+		case string:
+			return []string{t}, nil
+		}
+	case OptMap:
+		switch t := v.(type) {
+		case map[string]string:
+			m := make(map[string]string, len(t))
+			for k, e := range t {
+				m[strings.TrimSpace(k)] = strings.TrimSpace(e)
+			}
+			return m, nil
+		case map[string]interface{}:
+			m := make(map[string]string, len(t))
+			for k, e := range t {
+				m[k] = fmt.Sprintf("%v", e)
+			}
+			return m, nil
+		case map[interface{}]interface{}:
+			m := make(map[string]string, len(t))
+			for k, e := range t {
+				m[fmt.Sprintf("%v", k)] = fmt.Sprintf("%v", e)
+			}
+			return m, nil
+		}
+	case OptDuration:
+		switch t := v.(type) {
+		case time.Duration:
+			return t, nil
+		case int:
+			return time.Duration(t) * time.Second, nil
+		case int64:
+			return time.Duration(t) * time.Second, nil
+		case float64:
+			return time.Duration(t * float64(time.Second)), nil
+		case string:
+			if d, err := time.ParseDuration(t); err == nil {
+				return d, nil
+			}
+			// Plain numbers are seconds.
+			if f, err := strconv.ParseFloat(t, 64); err == nil {
+				return time.Duration(f * float64(time.Second)), nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("Incorrect value for \"%s\": %v", o.Key, v)
+}
+
+// String returns a string option.
+func (opts Options) String(key string) string {
+	s, _ := opts[key].(string)
+	return s
+}
+
+// Int returns an integer option.
+func (opts Options) Int(key string) int64 {
+	n, _ := opts[key].(int64)
+	return n
+}
+
+// Bool returns a boolean option.
+func (opts Options) Bool(key string) bool {
+	b, _ := opts[key].(bool)
+	return b
+}
+
+// List returns a list option.
+func (opts Options) List(key string) []string {
+	l, _ := opts[key].([]string)
+	return l
+}
+
+// Map returns a map option.
+func (opts Options) Map(key string) map[string]string {
+	m, _ := opts[key].(map[string]string)
+	return m
+}
+
+// Duration returns a duration option.
+func (opts Options) Duration(key string) time.Duration {
+	d, _ := opts[key].(time.Duration)
+	return d
+}
+
+// IsSet returns true if an option is set.
+func (opts Options) IsSet(key string) bool {
+	_, ok := opts[key]
+	return ok
+}
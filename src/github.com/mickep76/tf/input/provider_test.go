@@ -0,0 +1,171 @@
+package input
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/url"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func Test_Convert(t *testing.T) {
+	o := Option{Key: "port", Type: OptInt}
+	if v, err := o.Convert("2379"); err != nil || v != int64(2379) {
+		t.Error("Convert didn't return expected result")
+	} else {
+		t.Log("Convert test passes")
+	}
+
+	if v, err := o.Convert(float64(3306)); err != nil || v != int64(3306) {
+		t.Error("Convert didn't return expected result")
+	} else {
+		t.Log("Convert test passes")
+	}
+
+	if _, err := o.Convert("abc"); err == nil {
+		t.Error("Convert didn't return expected error")
+	} else {
+		t.Log("Convert test passes")
+	}
+
+	o = Option{Key: "timeout", Type: OptDuration}
+	for _, v := range []interface{}{"1m30s", "90", int64(90), float64(90)} {
+		if d, err := o.Convert(v); err != nil || d != 90*time.Second {
+			t.Errorf("Convert didn't return expected result for: %v", v)
+		} else {
+			t.Log("Convert test passes")
+		}
+	}
+
+	o = Option{Key: "etcd_endpoints", Type: OptList}
+	if v, err := o.Convert([]string{"http://a:2379, http://b:2379", "http://c:2379"}); err != nil ||
+		!reflect.DeepEqual(v, []string{"http://a:2379", "http://b:2379", "http://c:2379"}) {
+		t.Errorf("Convert didn't return expected list: %v", v)
+	} else {
+		t.Log("Convert test passes")
+	}
+
+	o = Option{Key: "exec_args", Type: OptArgs}
+	if v, err := o.Convert([]string{"-c", "echo a, b "}); err != nil || !reflect.DeepEqual(v, []string{"-c", "echo a, b "}) {
+		t.Errorf("Convert didn't return expected arguments: %v", v)
+	} else {
+		t.Log("Convert test passes")
+	}
+
+	if v, err := o.Convert("a,b"); err != nil || !reflect.DeepEqual(v, []string{"a,b"}) {
+		t.Errorf("Convert didn't return expected arguments: %v", v)
+	} else {
+		t.Log("Convert test passes")
+	}
+}
+
+func Test_Missing(t *testing.T) {
+	p, _ := GetProvider("http")
+	opts := NewOptions(p)
+	if k := Missing(p, opts); k != "http_url" {
+		t.Error("Missing didn't return expected result")
+	} else {
+		t.Log("Missing test passes")
+	}
+
+	opts["http_url"] = "http://localhost"
+	if k := Missing(p, opts); k != "" {
+		t.Error("Missing didn't return expected result")
+	} else {
+		t.Log("Missing test passes")
+	}
+}
+
+// flakyProvider is a remote provider that fails a number of times before returning a result.
+type flakyProvider struct {
+	fails *int
+	err   error
+}
+
+func (flakyProvider) Namespace() string { return "" }
+func (flakyProvider) Options() []Option { return nil }
+func (flakyProvider) Remote() bool      { return true }
+
+func (p flakyProvider) Get(opts Options, data map[string]interface{}) (interface{}, error) {
+	if *p.fails > 0 {
+		*p.fails--
+		return nil, p.err
+	}
+	return "ok", nil
+}
+
+func Test_Get(t *testing.T) {
+	fails := 1
+	p := flakyProvider{fails: &fails, err: &statusError{code: 503, msg: "Unavailable"}}
+	opts := NewOptions(p)
+	opts["retry_backoff"] = time.Millisecond
+	if _, err := Get("flaky", p, opts, nil); err == nil {
+		t.Error("Get didn't return expected error")
+	} else {
+		t.Log("Get test passes")
+	}
+
+	fails = 1
+	opts["retries"] = int64(1)
+	if v, err := Get("flaky", p, opts, nil); err != nil || v != "ok" {
+		t.Error("Get didn't retry")
+	} else {
+		t.Log("Get test passes")
+	}
+
+	fails = 1
+	p.err = &statusError{code: 404, msg: "Not Found"}
+	if _, err := Get("flaky", p, opts, nil); err == nil || fails != 0 {
+		t.Error("Get shouldn't retry errors that aren't retryable")
+	} else {
+		t.Log("Get test passes")
+	}
+}
+
+func Test_Retryable(t *testing.T) {
+	tests := []struct {
+		err   error
+		retry bool
+	}{
+		{errors.New("Incorrect input"), false},
+		{&statusError{code: 400}, false},
+		{&statusError{code: 429}, true},
+		{&statusError{code: 502}, true},
+		{temporaryError{err: errors.New("Busy")}, true},
+		{&net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, true},
+		{&url.Error{Op: "Get", URL: "http://example.com", Err: &net.DNSError{Err: "no such host", Name: "example.com"}}, false},
+		{&url.Error{Op: "Get", URL: "http://example.com", Err: context.DeadlineExceeded}, true},
+		{io.ErrUnexpectedEOF, true},
+	}
+
+	for _, tt := range tests {
+		if r := retryable(tt.err); r != tt.retry {
+			t.Errorf("retryable(%v) returned %v, expected %v", tt.err, r, tt.retry)
+		}
+	}
+	t.Log("Retryable test passes")
+}
+
+func Test_Idempotent(t *testing.T) {
+	p := httpProvider{}
+	opts := NewOptions(p)
+	if !p.Idempotent(opts) {
+		t.Error("HTTP GET should be idempotent")
+	}
+
+	opts["http_method"] = "POST"
+	if p.Idempotent(opts) {
+		t.Error("HTTP POST shouldn't be idempotent")
+	}
+
+	opts["http_retry_non_idempotent"] = true
+	if !p.Idempotent(opts) {
+		t.Error("HTTP POST should be retried with http_retry_non_idempotent")
+	} else {
+		t.Log("Idempotent test passes")
+	}
+}
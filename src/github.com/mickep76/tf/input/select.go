@@ -0,0 +1,120 @@
+package input
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// selectStep is a step in a select path, either a key, an index or iterating over all elements.
+type selectStep struct {
+	key   string
+	index int
+	isIdx bool
+	iter  bool
+}
+
+// parseSelect parses a select path such as ".data.items[0]", "$.data.items[*].name" or `.["a.b"]`.
+func parseSelect(path string) ([]selectStep, error) {
+	p := strings.TrimPrefix(strings.TrimSpace(path), "$")
+
+	var steps []selectStep
+	for len(p) > 0 {
+		switch p[0] {
+		case '.':
+			p = p[1:]
+			n := strings.IndexAny(p, ".[")
+			if n < 0 {
+				n = len(p)
+			}
+			if n > 0 {
+				steps = append(steps, selectStep{key: p[:n]})
+			}
+			p = p[n:]
+		case '[':
+			n := strings.Index(p, "]")
+			if n < 0 {
+				return nil, fmt.Errorf("Missing \"]\" in select path: %s", path)
+			}
+			s := strings.TrimSpace(p[1:n])
+			p = p[n+1:]
+
+			switch {
+			case s == "" || s == "*":
+				steps = append(steps, selectStep{iter: true})
+			case len(s) > 1 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0]:
+				steps = append(steps, selectStep{key: s[1 : len(s)-1]})
+			default:
+				i, err := strconv.Atoi(s)
+				if err != nil {
+					return nil, fmt.Errorf("Incorrect index \"%s\" in select path: %s", s, path)
+				}
+				steps = append(steps, selectStep{index: i, isIdx: true})
+			}
+		default:
+			return nil, fmt.Errorf("Incorrect select path, needs to start with \".\" or \"[\": %s", path)
+		}
+	}
+	return steps, nil
+}
+
+// selectSteps applies the steps to a value.
+func selectSteps(v interface{}, steps []selectStep, path string) (interface{}, error) {
+	for i, s := range steps {
+		switch {
+		case s.iter:
+			l, ok := v.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("Can't iterate over %T in select path: %s", v, path)
+			}
+
+			r := make([]interface{}, len(l))
+			for j, e := range l {
+				var err error
+				if r[j], err = selectSteps(e, steps[i+1:], path); err != nil {
+					return nil, err
+				}
+			}
+			return r, nil
+		case s.isIdx:
+			l, ok := v.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("Can't index %T in select path: %s", v, path)
+			}
+
+			n := s.index
+			if n < 0 {
+				n += len(l)
+			}
+			if n < 0 || n >= len(l) {
+				return nil, fmt.Errorf("Index %d out of range in select path: %s", s.index, path)
+			}
+			v = l[n]
+		default:
+			var ok bool
+			switch m := v.(type) {
+			case map[string]interface{}:
+				v, ok = m[s.key]
+			case map[interface{}]interface{}:
+				v, ok = m[s.key]
+			default:
+				return nil, fmt.Errorf("Can't get key \"%s\" from %T in select path: %s", s.key, v, path)
+			}
+			if !ok {
+				return nil, fmt.Errorf("Key \"%s\" doesn't exist in select path: %s", s.key, path)
+			}
+		}
+	}
+	return v, nil
+}
+
+// Select returns the sub-tree of the data for a path, using a subset of jq and JSONPath. Keys are separated with
+// "." or given as ["key"], lists are indexed with [n] and [] or [*] selects from all elements in a list.
+// An empty path returns the data as is.
+func Select(v interface{}, path string) (interface{}, error) {
+	steps, err := parseSelect(path)
+	if err != nil {
+		return nil, err
+	}
+	return selectSteps(v, steps, path)
+}
@@ -0,0 +1,47 @@
+package input
+
+import (
+	"testing"
+)
+
+func Test_Select(t *testing.T) {
+	v, _ := UnmarshalData([]byte(`{"data": {"items": [{"name": "host1"}, {"name": "host2"}], "a.b": 1}}`), JSON)
+
+	if l, err := Select(v, ".data.items"); err != nil || len(l.([]interface{})) != 2 {
+		t.Errorf("Select didn't return expected result: %v %v", l, err)
+	} else {
+		t.Log("Select test passes")
+	}
+
+	if s, err := Select(v, "$.data.items[-1].name"); err != nil || s != "host2" {
+		t.Errorf("Select didn't return expected result: %v %v", s, err)
+	} else {
+		t.Log("Select test passes")
+	}
+
+	if l, err := Select(v, `.data.items[*]["name"]`); err != nil || l.([]interface{})[0] != "host1" {
+		t.Errorf("Select didn't return expected result: %v %v", l, err)
+	} else {
+		t.Log("Select test passes")
+	}
+
+	if s, err := Select(v, `.data["a.b"]`); err != nil || s != float64(1) {
+		t.Errorf("Select didn't return expected result: %v %v", s, err)
+	} else {
+		t.Log("Select test passes")
+	}
+
+	if r, err := Select(v, ""); err != nil || r == nil {
+		t.Errorf("Select didn't return expected result: %v %v", r, err)
+	} else {
+		t.Log("Select test passes")
+	}
+
+	for _, p := range []string{".data.missing", ".data.items[5]", ".data.items.name", "data"} {
+		if _, err := Select(v, p); err == nil {
+			t.Errorf("Select didn't return expected error for: %s", p)
+		} else {
+			t.Log("Select test passes")
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package input
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	log "github.com/Sirupsen/logrus"
+)
+
+// GetSQL queries a database using a database/sql driver, rows are returned as a list of maps keyed by column.
+// If typed is false values are returned as strings and NULL as "NULL", otherwise the type returned by the driver
+// is kept. A timeout of 0 means no timeout.
+func GetSQL(driver string, dsn string, qry string, typed bool, timeout time.Duration) ([]interface{}, error) {
+	dbo, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	defer dbo.Close()
+
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	err = dbo.PingContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Execute query: %s", qry)
+	rows, err := dbo.QueryContext(ctx, qry)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	log.Infof("Get result from query")
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var data []interface{}
+
+	values := make([]interface{}, len(columns))
+	scanArgs := make([]interface{}, len(values))
+
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+
+	for rows.Next() {
+		err = rows.Scan(scanArgs...)
+		if err != nil {
+			return nil, err
+		}
+
+		res := make(map[string]interface{})
+		for i, col := range values {
+			if b, ok := col.([]byte); ok {
+				col = string(b)
+			}
+
+			if !typed {
+				switch v := col.(type) {
+				case nil:
+					col = "NULL"
+				case time.Time:
+					col = v.Format(time.RFC3339Nano)
+				default:
+					col = fmt.Sprintf("%v", v)
+				}
+			}
+			res[columns[i]] = col
+		}
+		data = append(data, res)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
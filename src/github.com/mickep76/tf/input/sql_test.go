@@ -0,0 +1,71 @@
+package input
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+// fakeDriver is a database/sql driver returning a fixed result for any query.
+type fakeDriver struct{}
+
+type fakeConn struct{}
+
+type fakeStmt struct{}
+
+type fakeRows struct {
+	i int
+}
+
+var fakeResult = [][]driver.Value{
+	{"host1.example.com", []byte("abc123")},
+	{"host2.example.com", nil},
+}
+
+func init() {
+	sql.Register("fake", fakeDriver{})
+}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) { return fakeConn{}, nil }
+
+func (fakeConn) Prepare(qry string) (driver.Stmt, error) { return fakeStmt{}, nil }
+func (fakeConn) Close() error                            { return nil }
+func (fakeConn) Begin() (driver.Tx, error)               { return nil, errors.New("not supported") }
+
+func (fakeStmt) Close() error  { return nil }
+func (fakeStmt) NumInput() int { return 0 }
+func (fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+func (fakeStmt) Query(args []driver.Value) (driver.Rows, error) { return &fakeRows{}, nil }
+
+func (r *fakeRows) Columns() []string { return []string{"host", "serialno"} }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.i >= len(fakeResult) {
+		return io.EOF
+	}
+	copy(dest, fakeResult[r.i])
+	r.i++
+	return nil
+}
+
+func Test_GetSQL(t *testing.T) {
+	rows, err := GetSQL("fake", "", "SELECT host, serialno FROM hosts", false, time.Second)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(rows) != 2 {
+		t.Error("GetSQL didn't return expected number of rows")
+	} else if r := rows[0].(map[string]interface{}); r["host"] != "host1.example.com" || r["serialno"] != "abc123" {
+		t.Error("GetSQL didn't return expected result")
+	} else if r := rows[1].(map[string]interface{}); r["serialno"] != "NULL" {
+		t.Error("GetSQL didn't return expected result for NULL")
+	} else {
+		t.Log("GetSQL test passes")
+	}
+}
@@ -0,0 +1,44 @@
+//go:build sqlite
+// +build sqlite
+
+package input
+
+import (
+	"fmt"
+	"os"
+
+	log "github.com/Sirupsen/logrus"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type sqliteProvider struct{}
+
+func init() {
+	Register("sqlite", sqliteProvider{})
+}
+
+func (sqliteProvider) Namespace() string {
+	return "SQLite"
+}
+
+func (sqliteProvider) Options() []Option {
+	return []Option{
+		{Key: "sqlite_path", Description: "SQLite database file", Required: true},
+		{Key: "sqlite_query", Description: "SQLite query", Required: true},
+	}
+}
+
+func (sqliteProvider) Get(opts Options, data map[string]interface{}) (interface{}, error) {
+	return GetSQLite(opts.String("sqlite_path"), opts.String("sqlite_query"))
+}
+
+// GetSQLite queries a SQLite database file, the database is opened read-only and column types are preserved.
+func GetSQLite(path string, qry string) ([]interface{}, error) {
+	if _, err := os.Stat(path); err != nil {
+		log.Errorf("File doesn't exist: %s", path)
+		return nil, err
+	}
+
+	log.Infof("Opening SQLite database: %s", path)
+	return GetSQL("sqlite3", fmt.Sprintf("file:%s?mode=ro", path), qry, true, 0)
+}
@@ -0,0 +1,48 @@
+//go:build sqlite
+// +build sqlite
+
+package input
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_GetSQLite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tf")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	fn := filepath.Join(dir, "inventory.db")
+	db, err := sql.Open("sqlite3", fn)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	for _, s := range []string{
+		"CREATE TABLE hosts (name TEXT, cpus INTEGER, load REAL, serialno TEXT)",
+		"INSERT INTO hosts VALUES ('host1.example.com', 4, 0.5, NULL)",
+	} {
+		if _, err := db.Exec(s); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+	db.Close()
+
+	rows, err := GetSQLite(fn, "SELECT * FROM hosts")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(rows) != 1 {
+		t.Error("GetSQLite didn't return expected number of rows")
+	} else if r := rows[0].(map[string]interface{}); r["name"] != "host1.example.com" || r["cpus"] != int64(4) || r["load"] != 0.5 || r["serialno"] != nil {
+		t.Errorf("GetSQLite didn't return expected result: %v", r)
+	} else {
+		t.Log("GetSQLite test passes")
+	}
+}
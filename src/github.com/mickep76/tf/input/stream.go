@@ -0,0 +1,98 @@
+package input
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+)
+
+// unmarshalYAML unmarshal a single YAML document, the top-level map is returned as map[string]interface{}.
+func unmarshalYAML(cont []byte) (interface{}, error) {
+	var v interface{}
+	if err := yaml.Unmarshal(cont, &v); err != nil {
+		return nil, err
+	}
+
+	// YAML maps use interface{} keys, convert the top-level map for use as a namespace.
+	if m, ok := v.(map[interface{}]interface{}); ok {
+		v2 := make(map[string]interface{})
+		for k, e := range m {
+			v2[fmt.Sprintf("%v", k)] = e
+		}
+		return v2, nil
+	} else if v == nil {
+		return make(map[string]interface{}), nil
+	}
+	return v, nil
+}
+
+// yamlEmpty returns true if a YAML document only contains whitespace and comments.
+func yamlEmpty(doc []byte) bool {
+	for _, l := range bytes.Split(doc, []byte("\n")) {
+		l = bytes.TrimSpace(l)
+		if len(l) > 0 && l[0] != '#' {
+			return false
+		}
+	}
+	return true
+}
+
+// SplitYAML splits a YAML stream into documents separated by "---" or ended by "...", empty documents are
+// skipped.
+func SplitYAML(cont []byte) [][]byte {
+	var docs [][]byte
+	var doc []byte
+	add := func() {
+		if !yamlEmpty(doc) {
+			docs = append(docs, doc)
+		}
+		doc = nil
+	}
+
+	for _, l := range bytes.SplitAfter(cont, []byte("\n")) {
+		t := bytes.TrimRight(l, "\r\n")
+		if bytes.HasPrefix(t, []byte("---")) && (len(t) == 3 || t[3] == ' ' || t[3] == '\t') {
+			add()
+			// Content can follow the marker on the same line, such as "--- !tag" or "--- value".
+			doc = append(doc, t[3:]...)
+			doc = append(doc, '\n')
+			continue
+		}
+		if bytes.Equal(bytes.TrimRight(t, " \t"), []byte("...")) {
+			add()
+			continue
+		}
+		doc = append(doc, l...)
+	}
+	add()
+	return docs
+}
+
+// UnmarshalNDJSON unmarshal newline delimited JSON also known as JSON Lines, returns a list with a document per
+// line. Empty lines are skipped.
+func UnmarshalNDJSON(cont []byte) ([]interface{}, error) {
+	l := []interface{}{}
+
+	s := bufio.NewScanner(bytes.NewReader(cont))
+	s.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for n := 1; s.Scan(); n++ {
+		b := bytes.TrimSpace(s.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+
+		var v interface{}
+		if err := json.Unmarshal(b, &v); err != nil {
+			return nil, fmt.Errorf("NDJSON line %d: %v", n, err)
+		}
+		l = append(l, v)
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
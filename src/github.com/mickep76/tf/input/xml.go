@@ -0,0 +1,166 @@
+package input
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// xmlURL is the namespace of the predefined "xml" prefix such as in "xml:lang".
+const xmlURL = "http://www.w3.org/XML/1998/namespace"
+
+// xmlnsPrefix returns the prefix declared by a namespace declaration such as xmlns:x="...", the default namespace
+// has an empty prefix. It returns false if the attribute isn't a namespace declaration.
+func xmlnsPrefix(n xml.Name) (string, bool) {
+	switch {
+	case n.Space == "xmlns":
+		return n.Local, true
+	case n.Space == "" && n.Local == "xmlns":
+		return "", true
+	}
+	return "", false
+}
+
+// xmlNamespaces returns the namespace prefixes in scope for an element by namespace URI.
+func xmlNamespaces(start xml.StartElement, parent map[string]string) map[string]string {
+	var ns map[string]string
+	for _, a := range start.Attr {
+		p, ok := xmlnsPrefix(a.Name)
+		if !ok {
+			continue
+		}
+
+		if ns == nil {
+			ns = make(map[string]string, len(parent)+1)
+			for k, v := range parent {
+				ns[k] = v
+			}
+		}
+		ns[a.Value] = p
+	}
+
+	if ns == nil {
+		return parent
+	}
+	return ns
+}
+
+// xmlName returns the name of an element or attribute with the namespace prefix used in the document such as
+// "x:id", names in the default namespace have no prefix.
+func xmlName(n xml.Name, ns map[string]string) string {
+	if n.Space == "" {
+		return n.Local
+	}
+
+	// The decoder leaves an undeclared prefix as is.
+	p, ok := ns[n.Space]
+	if !ok {
+		p = n.Space
+	}
+	if p == "" {
+		return n.Local
+	}
+	return p + ":" + n.Local
+}
+
+// xmlElement decodes an element after its start element, elements with only text are returned as a string.
+func xmlElement(d *xml.Decoder, start xml.StartElement, ns map[string]string) (interface{}, error) {
+	m := make(map[string]interface{})
+	for _, a := range start.Attr {
+		if _, ok := xmlnsPrefix(a.Name); ok {
+			continue
+		}
+		m["@"+xmlName(a.Name, ns)] = a.Value
+	}
+
+	var text strings.Builder
+	for {
+		t, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch e := t.(type) {
+		case xml.StartElement:
+			cns := xmlNamespaces(e, ns)
+			v, err := xmlElement(d, e, cns)
+			if err != nil {
+				return nil, err
+			}
+
+			// Repeated elements become a list.
+			k := xmlName(e.Name, cns)
+			switch p := m[k].(type) {
+			case nil:
+				m[k] = v
+			case []interface{}:
+				m[k] = append(p, v)
+			default:
+				m[k] = []interface{}{p, v}
+			}
+		case xml.CharData:
+			text.Write(e)
+		case xml.EndElement:
+			s := strings.TrimSpace(text.String())
+			if len(m) == 0 {
+				return s, nil
+			}
+			if s != "" {
+				m["#text"] = s
+			}
+			return m, nil
+		}
+	}
+}
+
+// xmlCharsetReader converts ISO-8859-1 and US-ASCII to UTF-8, other encodings than UTF-8 aren't supported.
+func xmlCharsetReader(label string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(label) {
+	case "iso-8859-1", "iso8859-1", "iso_8859-1", "latin1", "l1", "us-ascii", "ascii":
+	default:
+		return nil, fmt.Errorf("Unsupported XML encoding: %s", label)
+	}
+
+	b, err := ioutil.ReadAll(input)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each byte is the same code point in Unicode.
+	var buf bytes.Buffer
+	for _, c := range b {
+		buf.WriteRune(rune(c))
+	}
+	return &buf, nil
+}
+
+// UnmarshalXML unmarshal XML data. Elements are returned as nested maps keyed by the element name, repeated
+// elements as lists and attributes as keys prefixed with "@". Elements with only text are returned as a string,
+// otherwise the text is kept as "#text". Names keep the namespace prefix used in the document such as "@x:id" and
+// namespace declarations are skipped.
+func UnmarshalXML(cont []byte) (map[string]interface{}, error) {
+	d := xml.NewDecoder(bytes.NewReader(cont))
+	d.CharsetReader = xmlCharsetReader
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			return nil, errors.New("Missing root element in XML data")
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if e, ok := t.(xml.StartElement); ok {
+			ns := xmlNamespaces(e, map[string]string{xmlURL: "xml"})
+			v, err := xmlElement(d, e, ns)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{xmlName(e.Name, ns): v}, nil
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package input
+
+import (
+	"testing"
+)
+
+func Test_UnmarshalXML(t *testing.T) {
+	v, err := UnmarshalXML([]byte(`<?xml version="1.0"?>
+<inventory site="sto">
+  <host id="1"><name>web1</name></host>
+  <host id="2"><name>web2</name><role>db</role></host>
+  <note lang="en">maintenance</note>
+  <empty/>
+</inventory>`))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	inv, ok := v["inventory"].(map[string]interface{})
+	if !ok || inv["@site"] != "sto" || inv["empty"] != "" {
+		t.Fatalf("UnmarshalXML didn't return expected result: %v", v)
+	}
+
+	if hosts, ok := inv["host"].([]interface{}); !ok || len(hosts) != 2 || hosts[1].(map[string]interface{})["role"] != "db" ||
+		hosts[0].(map[string]interface{})["@id"] != "1" {
+		t.Errorf("UnmarshalXML didn't return expected repeated elements: %v", inv["host"])
+	} else {
+		t.Log("UnmarshalXML test passes")
+	}
+
+	if n, ok := inv["note"].(map[string]interface{}); !ok || n["#text"] != "maintenance" || n["@lang"] != "en" {
+		t.Errorf("UnmarshalXML didn't return expected text: %v", inv["note"])
+	} else {
+		t.Log("UnmarshalXML test passes")
+	}
+
+	if _, err := UnmarshalXML([]byte("<a><b></a>")); err == nil {
+		t.Error("UnmarshalXML didn't return expected error")
+	} else {
+		t.Log("UnmarshalXML test passes")
+	}
+
+	v, err = UnmarshalXML([]byte("<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?>\n<city>G\xf6teborg</city>"))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if v["city"] != "Göteborg" {
+		t.Errorf("UnmarshalXML didn't return expected ISO-8859-1 text: %v", v)
+	} else {
+		t.Log("UnmarshalXML test passes")
+	}
+
+	if _, err := UnmarshalXML([]byte("<?xml version=\"1.0\" encoding=\"EBCDIC\"?>\n<a/>")); err == nil {
+		t.Error("UnmarshalXML didn't return expected error for an unsupported encoding")
+	} else {
+		t.Log("UnmarshalXML test passes")
+	}
+
+	v, err = UnmarshalXML([]byte(`<?xml version="1.0"?>
+<inventory xmlns="urn:inventory" xmlns:x="urn:extra" xml:lang="en">
+  <host id="1" x:id="a1"><name>web1</name><x:name>web1.example.com</x:name></host>
+</inventory>`))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	inv, _ = v["inventory"].(map[string]interface{})
+	h, _ := inv["host"].(map[string]interface{})
+	if len(inv) != 2 || inv["@xml:lang"] != "en" || h["@id"] != "1" || h["@x:id"] != "a1" || h["name"] != "web1" ||
+		h["x:name"] != "web1.example.com" {
+		t.Errorf("UnmarshalXML didn't return expected namespaced result: %v", v)
+	} else {
+		t.Log("UnmarshalXML test passes")
+	}
+}
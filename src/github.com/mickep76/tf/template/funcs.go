@@ -44,11 +44,13 @@ var funcs = template.FuncMap{
 
 // Parse template.
 func Compile(s string, d map[string]interface{}) (*bytes.Buffer, error) {
-	t := template.Must(template.New("template").Funcs(funcs).Parse(s))
+	t, err := template.New("template").Funcs(funcs).Parse(s)
+	if err != nil {
+		return nil, err
+	}
 
 	b := new(bytes.Buffer)
-	err := t.Execute(b, d)
-	if err != nil {
+	if err := t.Execute(b, d); err != nil {
 		return nil, err
 	}
 	return b, nil
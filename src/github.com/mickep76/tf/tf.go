@@ -5,11 +5,9 @@ import (
 	"io/ioutil"
 	"os"
 	"os/user"
-	"reflect"
 	"strconv"
 
 	log "github.com/Sirupsen/logrus"
-	etcd "github.com/coreos/go-etcd/etcd"
 	"github.com/flosch/pongo2"
 	flags "github.com/jessevdk/go-flags"
 	"gopkg.in/yaml.v2"
@@ -19,31 +17,6 @@ import (
 	"github.com/mickep76/tf/template"
 )
 
-// Input options.
-type Input struct {
-	Name          *string
-	Type          *string
-	Path          *string
-	EtcdHost      *string
-	EtcdPort      *int64
-	EtcdDir       *string
-	HTTPUrl       *string
-	HTTPHeader    *string
-	HTTPFormat    *string
-	MySQLUser     *string
-	MySQLPassword *string
-	MySQLHost     *string
-	MySQLPort     *int64
-	MySQLDatabase *string
-	MySQLQuery    *string
-}
-
-// Merge namespaces.
-type Merge struct {
-	Name   string
-	Inputs []interface{}
-}
-
 func main() {
 	// Get the FileInfo struct describing the standard input.
 	fi, _ := os.Stdin.Stat()
@@ -54,34 +27,35 @@ func main() {
 
 	// Options.
 	var opts struct {
-		Verbose       bool    `short:"v" long:"verbose" description:"Verbose"`
-		Version       bool    `long:"version" description:"Version"`
-		Config        string  `short:"c" long:"config" description:"YAML, TOML or JSON config file"`
-		Input         *string `short:"i" long:"input" description:"Input"`
-		InpFormat     string  `short:"F" long:"input-format" description:"Data serialization format YAML, TOML or JSON" default:"YAML"`
-		InpFile       *string `short:"f" long:"input-file" description:"Input file, data serialization format used is based on the file extension"`
-		TemplFile     *string `short:"t" long:"template" description:"Template file"`
-		TemplLang     string  `short:"l" long:"template-lang" description:"Template language text or pongo2" default:"pongo2"`
-		OutpFile      *string `short:"o" long:"output" description:"Output file (STDOUT)"`
-		Permission    string  `short:"p" long:"permission" description:"File permissions in octal" default:"644"`
-		Owner         *string `short:"O" long:"owner" description:"File Owner"`
-		HWInfo        bool    `short:"H" long:"hwinfo" description:"Include hardware info as input"`
-		EtcdHost      *string `long:"etcd-host" description:"Etcd Host"`
-		EtcdPort      int     `long:"etcd-port" description:"Etcd Port" default:"2379"`
-		EtcdDir       string  `long:"etcd-dir" description:"Etcd Dir" default:"/"`
-		HTTPUrl       *string `long:"http-url" description:"HTTP Url"`
-		HTTPHeader    string  `long:"http-header" description:"HTTP Header" default:"Accept: application/json"`
-		HTTPFormat    string  `long:"http-format" description:"HTTP Format" default:"JSON"`
-		MySQLUser     *string `long:"mysql-user" description:"MySQL user"`
-		MySQLPassword *string `long:"mysql-password" description:"MySQL password"`
-		MySQLHost     *string `long:"mysql-host" description:"MySQL host"`
-		MySQLPort     int64   `long:"mysql-port" description:"MySQL port" default:"3306"`
-		MySQLDatabase *string `long:"mysql-database" description:"MySQL database"`
-		MySQLQuery    *string `long:"mysql-query" description:"MySQL query"`
+		Verbose    bool     `short:"v" long:"verbose" description:"Verbose"`
+		Version    bool     `long:"version" description:"Version"`
+		Config     string   `short:"c" long:"config" description:"YAML, TOML, JSON or HCL config file"`
+		Input      []string `short:"i" long:"input" description:"Input, can be repeated and is merged in order"`
+		InpFormat  *string  `short:"F" long:"input-format" description:"Data serialization format YAML, TOML, JSON, NDJSON, INI, PROPERTIES, DOTENV, CSV, TSV, XML or HCL, for the input file it overrides the file extension" default-mask:"YAML"`
+		InpFile    []string `short:"f" long:"input-file" description:"Input file, data serialization format used is based on the file extension, can be repeated and is merged in order"`
+		InpDir     []string `short:"d" long:"input-dir" description:"Input directory, files are loaded with the relative path as nested keys, can be repeated and is merged in order"`
+		InpInclude []string `long:"input-dir-include" description:"Glob for files to include in the input directory, can be repeated"`
+		InpExclude []string `long:"input-dir-exclude" description:"Glob for files and directories to exclude from the input directory, can be repeated"`
+		ArrayMerge string   `long:"array-merge" description:"How arrays are merged for repeated inputs" choice:"replace" choice:"append" default:"replace"`
+		InpSelect  *string  `long:"input-file-select" description:"Select a sub-tree of the input file such as .data.items"`
+		InpDelim   *string  `long:"input-file-csv-delimiter" description:"CSV delimiter for the input file, defaults to \",\" for CSV and tab for TSV"`
+		InpKey     *string  `long:"input-file-csv-key" description:"CSV column to key rows by for the input file, returns a map instead of a list"`
+		TemplFile  *string  `short:"t" long:"template" description:"Template file"`
+		TemplLang  string   `short:"l" long:"template-lang" description:"Template language text or pongo2" default:"pongo2"`
+		OutpFile   *string  `short:"o" long:"output" description:"Output file (STDOUT)"`
+		Permission string   `short:"p" long:"permission" description:"File permissions in octal" default:"644"`
+		Owner      *string  `short:"O" long:"owner" description:"File Owner"`
+		HWInfo     bool     `short:"H" long:"hwinfo" description:"Include hardware info as input"`
 	}
 
 	// Parse options.
-	if _, err := flags.Parse(&opts); err != nil {
+	parser := flags.NewParser(&opts, flags.Default)
+	inpFlags, err := AddInputFlags(parser)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+
+	if _, err := parser.Parse(); err != nil {
 		ferr := err.(*flags.Error)
 		if ferr.Type == flags.ErrHelp {
 			os.Exit(0)
@@ -115,90 +89,98 @@ func main() {
 	}
 
 	// Get argument input.
-	if opts.Input != nil {
-		var f input.DataFmt
-		switch opts.InpFormat {
-		case "YAML":
-			f = input.YAML
-		case "TOML":
-			f = input.TOML
-		case "JSON":
-			f = input.JSON
-		default:
-			log.Fatal("Unsupported data format, needs to be YAML, JSON or TOML")
+	appendLists := opts.ArrayMerge == "append"
+	if len(opts.Input) > 0 {
+		format := "YAML"
+		if opts.InpFormat != nil {
+			format = *opts.InpFormat
 		}
 
-		var err error
-		data["Arg"], err = input.UnmarshalData([]byte(*opts.Input), f)
+		f, err := input.ParseDataFmt(format)
 		if err != nil {
 			log.Fatal(err.Error())
 		}
 
-		// Copy .Arg namespace to . for conveniencea.
-		for k, v := range data["Arg"].(map[string]interface{}) {
-			data[k] = v
+		for i, a := range opts.Input {
+			v, err := input.UnmarshalData([]byte(a), f)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+
+			if i == 0 {
+				data["Arg"] = v
+			} else {
+				data["Arg"] = input.DeepMerge(data["Arg"], v, appendLists)
+			}
+		}
+
+		// Copy .Arg namespace to . for conveniencea, only possible if it's a map.
+		if m, ok := data["Arg"].(map[string]interface{}); ok {
+			for k, v := range m {
+				data[k] = v
+			}
 		}
 	}
 
 	// Get file input.
-	if opts.InpFile != nil {
-		var err error
-		data["File"], err = input.LoadFile(*opts.InpFile, data)
-		if err != nil {
-			log.Fatal(err.Error())
+	for i, fn := range opts.InpFile {
+		p, _ := input.GetProvider("file")
+		o := input.NewOptions(p)
+		o["path"] = fn
+		for k, v := range map[string]*string{"format": opts.InpFormat, "select": opts.InpSelect, "csv_delimiter": opts.InpDelim, "csv_key": opts.InpKey} {
+			if v != nil {
+				o[k] = *v
+			}
 		}
-	}
 
-	// Get Etcd input.
-	if opts.EtcdHost != nil {
-		node := []string{fmt.Sprintf("http://%v:%v", *opts.EtcdHost, opts.EtcdPort)}
-		client := etcd.NewClient(node)
-		res, err := client.Get(opts.EtcdDir, true, true)
+		v, err := input.Get("File", p, o, data)
 		if err != nil {
 			log.Fatal(err.Error())
 		}
-		data["Etcd"] = input.EtcdMap(res.Node)
-	}
 
-	// Get http input.
-	if opts.HTTPUrl != nil {
-		var f input.DataFmt
-		switch opts.HTTPFormat {
-		case "YAML":
-			f = input.YAML
-		case "TOML":
-			f = input.TOML
-		case "JSON":
-			f = input.JSON
-		default:
-			log.Fatal("Unsupported data format, needs to be YAML, JSON or TOML")
+		if i == 0 {
+			data["File"] = v
+		} else {
+			data["File"] = input.DeepMerge(data["File"], v, appendLists)
 		}
+	}
 
-		var err error
-		data["HTTP"], err = input.GetHTTP(*opts.HTTPUrl, opts.HTTPHeader, f)
+	// Get directory input.
+	for i, dn := range opts.InpDir {
+		p, _ := input.GetProvider("dir")
+		o := input.NewOptions(p)
+		o["path"] = dn
+		o["include"] = opts.InpInclude
+		o["exclude"] = opts.InpExclude
+
+		v, err := input.Get("Dir", p, o, data)
 		if err != nil {
 			log.Fatal(err.Error())
 		}
-	}
 
-	// Get MySQL input.
-	if opts.MySQLHost != nil {
-		var err error
-
-		if opts.MySQLUser == nil {
-			log.Fatal("For input \"--mysql-host\" you need to specify \"--mysql-user\"")
+		if i == 0 {
+			data["Dir"] = v
+		} else {
+			data["Dir"] = input.DeepMerge(data["Dir"], v, appendLists)
 		}
-		if opts.MySQLPassword == nil {
-			log.Fatal("For input \"--mysql-host\" you need to specify \"--mysql-password\"")
+	}
+
+	// Get input from provider flags.
+	for _, f := range inpFlags.Providers {
+		o, set, err := inpFlags.Options(f)
+		if err != nil {
+			log.Fatal(err.Error())
 		}
-		if opts.MySQLDatabase == nil {
-			log.Fatal("For input \"--mysql-host\" you need to specify \"--mysql-database\"")
+		if !set {
+			continue
 		}
-		if opts.MySQLQuery == nil {
-			log.Fatal("For input \"--mysql-host\" you need to specify \"--mysql-query\"")
+
+		ns := f.Provider.Namespace()
+		if k := input.Missing(f.Provider, o); k != "" {
+			log.Fatalf("For input \"%v\" you need to specify \"--%v\"", ns, flagName(k))
 		}
 
-		data["MySQL"], err = input.GetMySQL(*opts.MySQLUser, *opts.MySQLPassword, *opts.MySQLHost, opts.MySQLPort, *opts.MySQLDatabase, *opts.MySQLQuery)
+		data[ns], err = input.Get(ns, f.Provider, o, data)
 		if err != nil {
 			log.Fatal(err.Error())
 		}
@@ -206,111 +188,56 @@ func main() {
 
 	// Load config file.
 	if opts.Config != "" {
-		cfg, err := input.LoadFile(opts.Config, data)
-		data["Cfg"] = cfg
+		cfg, err := LoadConfig(opts.Config, data)
 		if err != nil {
 			log.Fatal(err.Error())
 		}
+		data["Cfg"] = cfg.Values
 
-		if cfg["inputs"] == nil {
-			log.Fatal("No inputs specified in configuration file")
-		}
-
-		if reflect.ValueOf(cfg["inputs"]).Kind() != reflect.Map {
-			log.Fatal("Incorrect definition of inputs, it needs to be a map of values")
-		}
-
-		var defs CfgDefault
-		if cfg["defaults"] != nil {
-			defs, err = GetDefaults(cfg["defaults"].(map[string]interface{}))
+		var defs map[string]interface{}
+		if cfg.Defaults != nil {
+			defs, err = GetDefaults(cfg.Defaults)
 			if err != nil {
 				log.Fatal(err.Error())
 			}
 		}
 
-		for k, v := range cfg["inputs"].(map[string]interface{}) {
-			i, err := GetInput(k, v.(map[string]interface{}), defs)
+		// Remote options on the command line override [defaults].
+		rd, err := inpFlags.RemoteDefaults()
+		if err != nil {
+			log.Fatal(err.Error())
+		}
+		if defs == nil {
+			defs = make(map[string]interface{})
+		}
+		for k, v := range rd {
+			defs[k] = v
+		}
+
+		for k, inp := range cfg.Inputs {
+			i, err := GetInput(k, inp, defs)
 			if err != nil {
 				log.Fatal(err.Error())
 			}
 
-			if data[*i.Name] != nil {
-				log.Fatalf("Input name already exist's: %s", *i.Name)
+			if data[i.Name] != nil {
+				log.Fatalf("Input name already exist's: %s", i.Name)
 			}
 
-			switch *i.Type {
-			case "file":
-				var err error
-				data[*i.Name], err = input.LoadFile(*i.Path, data)
-				if err != nil {
-					log.Fatal(err.Error())
-				}
-			case "etcd":
-				node := []string{fmt.Sprintf("http://%v:%v", *i.EtcdHost, *i.EtcdPort)}
-				client := etcd.NewClient(node)
-				res, err := client.Get(*i.EtcdDir, true, true)
-				if err != nil {
-					log.Fatal(err.Error())
-				}
-				data[*i.Name] = input.EtcdMap(res.Node)
-			case "http":
-				var f input.DataFmt
-				switch *i.HTTPFormat {
-				case "YAML":
-					f = input.YAML
-				case "TOML":
-					f = input.TOML
-				case "JSON":
-					f = input.JSON
-				default:
-					log.Fatal("Unsupported data format, needs to be YAML, JSON or TOML")
-				}
-
-				var err error
-				data[*i.Name], err = input.GetHTTP(*i.HTTPUrl, *i.HTTPHeader, f)
-				if err != nil {
-					log.Fatal(err.Error())
-				}
-			case "mysql":
-				var err error
-				data[*i.Name], err = input.GetMySQL(*i.MySQLUser, *i.MySQLPassword, *i.MySQLHost, *i.MySQLPort, *i.MySQLDatabase, *i.MySQLQuery)
-				if err != nil {
-					log.Fatal(err.Error())
-				}
-			default:
-				log.Fatalf("Unknown type in configuration file .%v.Type: %v", *i.Name, *i.Type)
+			data[i.Name], err = input.Get(i.Name, i.Provider, i.Options, data)
+			if err != nil {
+				log.Fatal(err.Error())
 			}
 		}
 
-		if reflect.ValueOf(cfg["merge"]).Kind() == reflect.Map {
-			for k1, v1 := range cfg["merge"].(map[string]interface{}) {
-				var m Merge
-				m.Name = k1
-				for k2, v2 := range v1.(map[string]interface{}) {
-					switch k2 {
-					case "name":
-						m.Name = v2.(string)
-					case "inputs":
-						m.Inputs = v2.([]interface{})
-					default:
-						log.Fatalf("Invalid key in configuration file merge.%v.%v", k1, k2)
-					}
-				}
-
-				for i := range m.Inputs {
-					if data[m.Name] == nil {
-						data2 := make(map[string]interface{})
-						for k, v := range data[m.Inputs[i].(string)].(map[string]interface{}) {
-							data2[k] = v
-						}
-						data[m.Name] = data2
-					} else {
-						data2 := data[m.Name].(map[string]interface{})
-						for k, v := range data[m.Inputs[i].(string)].(map[string]interface{}) {
-							data2[k] = v
-						}
-					}
-				}
+		for k1, v1 := range cfg.Merge {
+			m, err := GetMerge(k1, v1)
+			if err != nil {
+				log.Fatal(err.Error())
+			}
+
+			if err := MergeInputs(m, data); err != nil {
+				log.Fatal(err.Error())
 			}
 		}
 	}